@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"slices"
+
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -19,6 +21,24 @@ type AddonSpec struct {
 	Namespace string        `json:"namespace,omitempty"`
 	Chart     *ChartInfo    `json:"chart,omitempty"`
 	Manifest  *ManifestInfo `json:"manifest,omitempty"`
+
+	// DependsOn is a list of addons of the same blueprint that must be Available before this addon is
+	// created or upgraded. Both chart and manifest addons can be referenced.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// AllDependencies returns the addons this addon depends on, including the deprecated chart dependencies
+func (a *AddonSpec) AllDependencies() []string {
+	deps := append([]string{}, a.DependsOn...)
+	if a.Chart != nil {
+		for _, dep := range a.Chart.DependsOn {
+			if !slices.Contains(deps, dep) {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
 }
 
 type ChartInfo struct {
@@ -31,9 +51,182 @@ type ChartInfo struct {
 	// +kubebuilder:validation:Required
 	Version string `json:"version"`
 
+	// DependsOn is a list of chart addons whose HelmRelease must be ready before this chart is installed.
+	// Deprecated: use AddonSpec.DependsOn, which also supports manifest addons.
 	DependsOn []string                      `json:"dependsOn,omitempty"`
 	Set       map[string]intstr.IntOrString `json:"set,omitempty"`
 	Values    *apiextensionsv1.JSON         `json:"values,omitempty"`
+
+	// ValuesFrom is an ordered list of ConfigMap and Secret keys in the blueprint-system namespace holding values
+	// for the chart. Values are merged in order, and the inline Values and Set entries are merged on top of them.
+	// +optional
+	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
+
+	// HelmOptions configures how the chart is installed, upgraded and uninstalled
+	// +optional
+	HelmOptions *HelmOptions `json:"helmOptions,omitempty"`
+
+	// Kustomize holds patches and image overrides applied to the rendered templates of the chart with a kustomize
+	// post-renderer, as with the values of manifest addons. Patches must be inline and can't have options, and
+	// images can't have a tag suffix.
+	// +optional
+	Kustomize *Values `json:"kustomize,omitempty"`
+
+	// SecretRef references a Secret in the blueprint-system namespace holding the credentials of the chart
+	// repository. For HTTP(S) repositories, the Secret must contain the "username" and "password" keys. For OCI
+	// repositories, the Secret can either contain these keys or be of type kubernetes.io/dockerconfigjson.
+	// +optional
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// CertSecretRef references a Secret in the blueprint-system namespace holding the CA bundle used to verify the
+	// TLS certificate of the chart repository under the "ca.crt" key. It can also hold a client certificate and key
+	// under the "tls.crt" and "tls.key" keys.
+	// +optional
+	CertSecretRef *SecretReference `json:"certSecretRef,omitempty"`
+
+	// PassCredentials allows the credentials of the repository to be passed to the host serving the chart when it
+	// differs from the host of the repository.
+	// +optional
+	PassCredentials bool `json:"passCredentials,omitempty"`
+
+	// Insecure allows connecting to an OCI registry over plain HTTP.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// This flag tells the controller how to handle the chart when its release isn't ready within the Timeout.
+	// Valid values are:
+	// - None (default) : No-op; the addon is marked Unhealthy and the release is left as is
+	// - Retry : The HelmRelease is reset and a new install or upgrade of the chart is forced, up to MaxRetries times
+	// - Uninstall : The release is uninstalled, and only installed again once the addon spec changes
+	// +kubebuilder:validation:Enum=None;Retry;Uninstall
+	// +optional
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+
+	// MaxRetries is the number of times the release is retried with the Retry failure policy. Once the retries are
+	// exhausted, the addon is marked Unhealthy and the release is left as is. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+
+	// Timeout for the install or upgrade of the chart as duration string (300s, 10m, 1h, etc)
+	// If the release is not ready after timeout duration, it will be handled by specified FailurePolicy.
+	// Unlike HelmOptions.Timeout, it covers the whole operation, including the retries of the helm-controller.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// HelmOptions configures the Helm actions of a chart addon
+// Unset fields keep the defaults of the operator.
+type HelmOptions struct {
+	// Wait makes Helm wait for the resources of the release to be ready before marking an install, upgrade or
+	// uninstall as successful. Defaults to false.
+	// +optional
+	Wait bool `json:"wait,omitempty"`
+
+	// Timeout is the time to wait for any individual Kubernetes operation during Helm actions, as a duration
+	// string (300s, 10m, 1h, etc). Defaults to 5m.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+
+	// InstallRetries is the number of retries of a failed install, the release being uninstalled between
+	// attempts. A negative value means unlimited retries. Defaults to 3.
+	// +optional
+	InstallRetries *int32 `json:"installRetries,omitempty"`
+
+	// UpgradeRetries is the number of retries of a failed upgrade, the release being remediated between
+	// attempts. A negative value means unlimited retries. Defaults to 3.
+	// +optional
+	UpgradeRetries *int32 `json:"upgradeRetries,omitempty"`
+
+	// RemediationStrategy is the remediation of a failed upgrade. Defaults to rollback.
+	// +kubebuilder:validation:Enum=rollback;uninstall
+	// +optional
+	RemediationStrategy string `json:"remediationStrategy,omitempty"`
+
+	// CRDs is the policy for the CRDs of the chart on install and upgrade. Skip neither creates nor updates
+	// CRDs, Create only creates missing CRDs, and CreateReplace also updates existing CRDs. Defaults to Create on
+	// install and Skip on upgrade, as with Helm.
+	// +kubebuilder:validation:Enum=Skip;Create;CreateReplace
+	// +optional
+	CRDs string `json:"crds,omitempty"`
+
+	// DisableHooks prevents the hooks of the chart from running on install, upgrade and uninstall
+	// +optional
+	DisableHooks bool `json:"disableHooks,omitempty"`
+
+	// DriftDetection configures how changes to the resources of the release made outside of Helm are handled
+	// +optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+
+	// Interval is the interval at which the release is reconciled, as a duration string (300s, 10m, 1h, etc).
+	// Defaults to 30s.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+}
+
+// DriftDetection configures the detection and correction of drift of the resources of a chart addon
+type DriftDetection struct {
+	// Mode is how drift is handled. enabled corrects drift, warn only reports it, and disabled ignores it.
+	// Defaults to enabled.
+	// +kubebuilder:validation:Enum=enabled;warn;disabled
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Ignore is a list of rules excluding fields of the resources from drift detection
+	// +optional
+	Ignore []DriftIgnoreRule `json:"ignore,omitempty"`
+}
+
+// DriftIgnoreRule excludes fields of the resources of a chart addon from drift detection
+type DriftIgnoreRule struct {
+	// Paths is a list of JSON Pointer (RFC 6901) paths of the fields to ignore
+	// +kubebuilder:validation:MinItems:=1
+	Paths []string `json:"paths"`
+
+	// Target selects the resources the rule applies to, all resources if not set
+	// +optional
+	Target *Selector `json:"target,omitempty"`
+}
+
+// ValuesReference references a key of a ConfigMap or Secret in the blueprint-system namespace holding chart values
+type ValuesReference struct {
+	// Kind of the object holding the values
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind"`
+
+	// Name of the object holding the values
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// ValuesKey is the key of the object holding the values, defaults to "values.yaml"
+	// +optional
+	ValuesKey string `json:"valuesKey,omitempty"`
+
+	// TargetPath is the path at which the value of the key is set, in the format of the Set entries. If empty, the
+	// value of the key is parsed as YAML and merged into the values.
+	// +optional
+	TargetPath string `json:"targetPath,omitempty"`
+
+	// Optional marks the reference as optional, so that a missing object or key is ignored
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// GetValuesKey returns the key holding the values, defaulting to "values.yaml"
+func (in ValuesReference) GetValuesKey() string {
+	if in.ValuesKey == "" {
+		return "values.yaml"
+	}
+	return in.ValuesKey
+}
+
+// SecretReference is a reference to a Secret in the blueprint-system namespace
+type SecretReference struct {
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
 }
 
 type ManifestInfo struct {
@@ -173,6 +366,10 @@ const (
 
 	// TypeComponentUnhealthy indicates the component is not functioning as intended.
 	TypeComponentUnhealthy StatusType = "Unhealthy"
+
+	// TypeComponentDryRun indicates that the chart of the component was rendered by a dry run without errors.
+	// The component is not installed, so it is neither available to its dependents nor part of the readiness of the blueprint.
+	TypeComponentDryRun StatusType = "DryRun"
 )
 
 type Status struct {
@@ -189,9 +386,107 @@ type Status struct {
 	Message string `json:"message,omitempty"`
 }
 
+// DryRunAction is the change a dry run predicts for an object
+type DryRunAction string
+
+const (
+	// DryRunActionCreate means that the object does not exist and would be created.
+	DryRunActionCreate DryRunAction = "Create"
+
+	// DryRunActionUpdate means that the object exists and would be changed.
+	DryRunActionUpdate DryRunAction = "Update"
+
+	// DryRunActionUnchanged means that the object exists and would not be changed.
+	DryRunActionUnchanged DryRunAction = "Unchanged"
+)
+
+// DryRunStatus is the result of rendering a chart addon without installing it
+type DryRunStatus struct {
+	// ObservedGeneration is the generation of the addon that was rendered.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ChartVersion is the version of the chart that was rendered.
+	ChartVersion string `json:"chartVersion,omitempty"`
+
+	// Objects are the objects rendered from the chart and the change each one would cause.
+	// +optional
+	Objects []DryRunObject `json:"objects,omitempty"`
+
+	// Errors are the rendering and validation errors found during the dry run.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+
+	// LastRenderTime is the time the chart was last rendered.
+	LastRenderTime metav1.Time `json:"lastRenderTime,omitempty"`
+}
+
+// DryRunObject is an object rendered during a dry run
+type DryRunObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+
+	// Action is the change the object would go through if the addon was installed.
+	// +kubebuilder:validation:Enum=Create;Update;Unchanged
+	Action DryRunAction `json:"action"`
+
+	// Changes are the paths of the fields that differ from the live object, only set for updates.
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+}
+
 // AddonStatus defines the observed state of Addon
 type AddonStatus struct {
 	Status `json:",inline"`
+
+	// ObservedGeneration is the generation of the addon the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// DryRun is the result of the last dry run, only set for chart addons with dryRun enabled.
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+
+	// FailedObjects lists the objects of a manifest addon that failed to be applied
+	// +optional
+	FailedObjects []ManifestObjectFailure `json:"failedObjects,omitempty"`
+
+	// Operation is the last install or upgrade of a chart addon with a timeout
+	// +optional
+	Operation *ChartOperation `json:"operation,omitempty"`
+}
+
+// ChartOperation describes an install or upgrade of the chart of an addon and its attempts
+type ChartOperation struct {
+	// Generation is the generation of the addon the operation was started for
+	Generation int64 `json:"generation"`
+
+	// Attempts is the number of times the release has been installed or upgraded for this operation
+	Attempts int32 `json:"attempts"`
+
+	// StartTime is the time the current attempt was started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Deadline is the time by which the release must be ready
+	Deadline metav1.Time `json:"deadline"`
+
+	// CompletionTime is the time the operation finished, either because the release became
+	// ready or because it timed out and the failure policy does not retry it.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// LastFailure describes the last failed attempt of the operation
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+
+	// LastFailureTime is the time of the last failed attempt of the operation
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// Uninstalled is set when the release was uninstalled as per the Uninstall failure policy
+	// +optional
+	Uninstalled bool `json:"uninstalled,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -4,12 +4,32 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DeletionPolicy defines what happens to the addons and resources of a blueprint when it is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete uninstalls the addons and deletes the resources of the blueprint
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the addons and resources of the blueprint in place
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
 // BlueprintSpec defines the desired state of Blueprint
 type BlueprintSpec struct {
 	// Components contains all the components that should be installed
 	Components Component `json:"components,omitempty"`
 	// Resources contains all object resources that should be installed
 	Resources Resources `json:"resources,omitempty"`
+
+	// DeletionPolicy defines what happens to the addons and resources of the blueprint when it is deleted.
+	// Valid values are:
+	// - Delete (default) : Addons are uninstalled in reverse dependency order, then the resources are deleted.
+	//			 The blueprint is only removed once all of them are gone.
+	// - Orphan : Addons and resources are left in place, and are no longer labelled as managed by the operator.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // Component defines the addons components that should be installed
@@ -31,12 +51,58 @@ type CertManagement struct {
 
 // BlueprintStatus defines the observed state of Blueprint
 type BlueprintStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration is the most recent generation of the Blueprint that was reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the blueprint. A blueprint may be one or more of
+	// Ready, Progressing or Degraded.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Addons contains the status summary of each addon declared in the blueprint
+	// +optional
+	Addons []AddonStatusSummary `json:"addons,omitempty"`
+
+	// Resources contains the status summary of each cert-manager resource declared in the blueprint
+	// +optional
+	Resources []ResourceStatusSummary `json:"resources,omitempty"`
+}
+
+// AddonStatusSummary is the status of a single addon as seen by the blueprint
+type AddonStatusSummary struct {
+	// Name of the addon
+	Name string `json:"name"`
+
+	// Namespace the addon is installed in
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Kind of the addon, either chart or manifest
+	Kind string `json:"kind"`
+
+	Status `json:",inline"`
+}
+
+// ResourceStatusSummary is the status of a single resource (e.g. Issuer, Certificate) as seen by the blueprint
+type ResourceStatusSummary struct {
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Namespace of the resource, empty for cluster scoped resources
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	Status `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether all the blueprint components are available."
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Blueprint is the Schema for the blueprints API
 type Blueprint struct {
@@ -1,22 +1,142 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // InstallationSpec defines the desired state of Installation
-type InstallationSpec struct{}
+type InstallationSpec struct {
+	// Registry is the image registry the images of the components are pulled from, unless overridden for a
+	// component. Defaults to the registry the operator is configured with.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+
+	// FluxCD configures the FluxCD controllers used to install the chart addons.
+	// +optional
+	FluxCD *ComponentSpec `json:"fluxcd,omitempty"`
+
+	// CertManager configures cert-manager, used to issue the certificates of the blueprints and of the webhook.
+	// +optional
+	CertManager *ComponentSpec `json:"certManager,omitempty"`
+
+	// Webhook configures the webhook validating the blueprints.
+	// +optional
+	Webhook *WebhookSpec `json:"webhook,omitempty"`
+}
+
+// ComponentSpec configures a component installed by the operator
+type ComponentSpec struct {
+	// Enabled indicates whether the component is installed. Defaults to true.
+	// Disabling a component that is already installed does not uninstall it.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// External indicates that the component is installed and managed outside of the operator.
+	// The operator then checks that the component is present in the cluster, but neither installs nor uninstalls it.
+	// +optional
+	External bool `json:"external,omitempty"`
+
+	// Image overrides the registry and the tags of the images of the component.
+	// +optional
+	Image *ImageSpec `json:"image,omitempty"`
+
+	// Replicas is the number of replicas of the deployments of the component. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources replaces the compute resources of the containers of the component.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Tolerations replaces the tolerations of the pods of the component.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector replaces the node selector of the pods of the component.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// ImageSpec overrides the registry and the tags of the images of a component
+type ImageSpec struct {
+	// Registry is the registry the images are pulled from.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+
+	// Tags overrides the tags of the images of the component, keyed by the name of the image without the registry,
+	// e.g. "fluxcd/helm-controller". The images of a component don't necessarily share the same version, so the
+	// tag of each image is overridden separately.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// WebhookSpec configures the webhook validating the blueprints
+type WebhookSpec struct {
+	// Enabled indicates whether the webhook is installed. Defaults to true.
+	// Disabling the webhook when it is already installed does not uninstall it.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// IsEnabled returns whether the component is enabled, which is the default
+func (s *ComponentSpec) IsEnabled() bool {
+	return s == nil || s.Enabled == nil || *s.Enabled
+}
+
+// IsExternal returns whether the component is installed and managed outside of the operator
+func (s *ComponentSpec) IsExternal() bool {
+	return s != nil && s.External
+}
+
+// IsEnabled returns whether the webhook is enabled, which is the default
+func (s *WebhookSpec) IsEnabled() bool {
+	return s == nil || s.Enabled == nil || *s.Enabled
+}
 
 // InstallationStatus defines the observed state of Installation
 type InstallationStatus struct {
-	// Conditions represents the latest observed set of conditions for the component. A component may be one or more of
-	// Ready, Progressing, Degraded or other customer types.
+	// ObservedGeneration is the generation of the spec the components were last installed with.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Components reports the versions of the components installed by the operator.
+	// +optional
+	Components []ComponentStatus `json:"components,omitempty"`
+
+	// Conditions represents the latest observed set of conditions of the installation. There is one condition per
+	// enabled component, named after the component, with one of the Ready, Installed, NotInstalled or Degraded reasons,
+	// and an aggregate Ready condition that is true once every enabled component is ready.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ComponentStatus reports the version of a component installed by the operator
+type ComponentStatus struct {
+	// Name is the name of the component.
+	Name string `json:"name"`
+
+	// Version is the version of the component installed in the cluster.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// PreviousVersion is the version of the component before it was last upgraded.
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// LastTransitionTime is the last time the version of the component changed.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether all the enabled components are ready."
+//+kubebuilder:printcolumn:name="FluxCD",type="string",JSONPath=".status.conditions[?(@.type==\"fluxcd\")].reason",description="The health of FluxCD."
+//+kubebuilder:printcolumn:name="Cert-Manager",type="string",JSONPath=".status.conditions[?(@.type==\"cert-manager\")].reason",description="The health of cert-manager."
+//+kubebuilder:printcolumn:name="Webhook",type="string",JSONPath=".status.conditions[?(@.type==\"webhook\")].reason",description="The health of the validation webhooks."
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Installation is the Schema for the installations API
 type Installation struct {
@@ -20,15 +20,84 @@ type ManifestSpec struct {
 	// +optional
 	Timeout string `json:"timeout"`
 
-	NewChecksum string           `json:"newChecksum,omitempty"`
-	Checksum    string           `json:"checksum"`
-	Values      *Values          `json:"values,omitempty"`
-	Objects     []ManifestObject `json:"objects,omitempty"`
+	// Checksum of the rendered manifest when the manifest was last created or updated.
+	// A change of checksum causes the manifest to be applied again, even if the url is unchanged.
+	// +optional
+	Checksum string  `json:"checksum,omitempty"`
+	Values   *Values `json:"values,omitempty"`
 }
 
 // ManifestStatus defines the observed state of Manifest
 type ManifestStatus struct {
 	Status `json:",inline"`
+
+	// ObservedGeneration is the generation of the manifest spec that was last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AppliedChecksum is the checksum of the rendered manifest that was last applied
+	// +optional
+	AppliedChecksum string `json:"appliedChecksum,omitempty"`
+
+	// Objects is the inventory of the objects that were last applied for the manifest
+	// +optional
+	Objects []ManifestObject `json:"objects,omitempty"`
+
+	// Conflicts lists the objects with fields owned by other field managers when the manifest was last applied.
+	// These objects are not applied and are also listed in Failures, until the other managers release the fields.
+	// +optional
+	Conflicts []ManifestConflict `json:"conflicts,omitempty"`
+
+	// Failures lists the objects that failed to be applied. They are applied again on the next reconcile.
+	// +optional
+	Failures []ManifestObjectFailure `json:"failures,omitempty"`
+
+	// Health lists the health of each object of the manifest
+	// +optional
+	Health []ManifestObjectHealth `json:"health,omitempty"`
+
+	// Operation records the latest install or upgrade of the manifest objects.
+	// It is used to enforce the timeout and failure policy across operator restarts.
+	// +optional
+	Operation *ManifestOperation `json:"operation,omitempty"`
+}
+
+// ManifestOperationType is the type of operation performed on the manifest objects
+type ManifestOperationType string
+
+const (
+	ManifestOperationInstall ManifestOperationType = "Install"
+	ManifestOperationUpgrade ManifestOperationType = "Upgrade"
+)
+
+// ManifestOperation describes an install or upgrade of the manifest objects and its attempts
+type ManifestOperation struct {
+	// Type is the type of the operation, Install or Upgrade
+	Type ManifestOperationType `json:"type"`
+
+	// Attempts is the number of times the objects have been applied for this operation
+	Attempts int32 `json:"attempts"`
+
+	// StartTime is the time the current attempt was started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Deadline is the time by which the manifest must be Available.
+	// It is not set if the manifest has no timeout.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+
+	// CompletionTime is the time the operation finished, either because the manifest became
+	// Available or because it timed out and the failure policy does not retry it.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// LastFailure describes the last failed attempt of the operation
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+
+	// LastFailureTime is the time of the last failed attempt of the operation
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
 }
 
 // ManifestObject consists of the fields required to update/delete an object
@@ -40,6 +109,50 @@ type ManifestObject struct {
 	Namespace string `json:"namespace"`
 }
 
+// ManifestConflict describes the fields of a manifest object that are owned by other field managers
+type ManifestConflict struct {
+	ManifestObject `json:",inline"`
+
+	// Fields lists the conflicting fields along with the managers that own them
+	Fields []string `json:"fields"`
+}
+
+// ManifestObjectFailure describes a manifest object that failed to be applied
+type ManifestObjectFailure struct {
+	ManifestObject `json:",inline"`
+
+	// Message is the error returned when applying the object
+	Message string `json:"message"`
+}
+
+// HealthStatus is the health of a manifest object, following the kstatus conventions
+type HealthStatus string
+
+const (
+	// HealthCurrent means the object is fully reconciled and ready
+	HealthCurrent HealthStatus = "Current"
+	// HealthInProgress means the object is being reconciled
+	HealthInProgress HealthStatus = "InProgress"
+	// HealthFailed means the object failed to reconcile
+	HealthFailed HealthStatus = "Failed"
+	// HealthTerminating means the object is being deleted
+	HealthTerminating HealthStatus = "Terminating"
+	// HealthNotFound means the object does not exist in the cluster
+	HealthNotFound HealthStatus = "NotFound"
+)
+
+// ManifestObjectHealth is the health of a manifest object
+type ManifestObjectHealth struct {
+	ManifestObject `json:",inline"`
+
+	// Health is the health of the object
+	Health HealthStatus `json:"health"`
+
+	// Message describes why the object is not Current
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.type",description="Whether the component is running and stable."
@@ -4,21 +4,28 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
@@ -32,6 +39,14 @@ const (
 	kindManifest = "manifest"
 	kindChart    = "chart"
 	finalizer    = "blueprint.mirantis.com/addon-finalizer"
+
+	kindConfigMap = "ConfigMap"
+	kindSecret    = "Secret"
+
+	// addonValuesFromIndex indexes addons by the ConfigMaps and Secrets they reference for values
+	addonValuesFromIndex = "addonvaluesfromindex"
+	// addonSourceIndex indexes chart addons by the HelmRepository and HelmChart objects their chart is fetched from
+	addonSourceIndex = "addonsourceindex"
 )
 
 // AddonReconciler reconciles a Addon object
@@ -43,6 +58,9 @@ type AddonReconciler struct {
 	helmController     *helm.Controller
 	manifestController *manifest.Controller
 
+	// restConfig is used to render charts against the cluster during dry runs
+	restConfig *rest.Config
+
 	SetupLogger logr.Logger
 }
 
@@ -54,6 +72,8 @@ type AddonReconciler struct {
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
 //+kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=helmrepositories;helmcharts,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -75,7 +95,7 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 
 	// Initialize k8s client
 	k8sClient := k8s.NewClient(logger, r.Client)
-	r.helmController = helm.NewHelmChartController(r.Client, k8sClient, logger)
+	r.helmController = helm.NewHelmChartController(r.Client, k8sClient, r.restConfig, logger)
 	r.manifestController = manifest.NewManifestController(r.Client, logger)
 
 	instance := &v1alpha1.Addon{}
@@ -126,11 +146,18 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	} else {
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(instance, finalizer) {
-			if err = r.deleteAddon(ctx, instance); err != nil {
+			deleted, err := r.deleteAddon(ctx, instance)
+			if err != nil {
 				// if fail to delete the addon here, return with error
 				// so that it can be retried
 				return ctrl.Result{}, err
 			}
+			if !deleted {
+				// the finalizer is kept until the release or manifest is gone, so that the blueprint only
+				// uninstalls the addons depending on this one afterwards
+				logger.Info("Waiting for the addon to be uninstalled", "Name", req.Name)
+				return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+			}
 
 			// remove our finalizer from the list and update it.
 			controllerutil.RemoveFinalizer(instance, finalizer)
@@ -147,8 +174,36 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	switch kind {
 	case kindChart:
 		chart := instance.Spec.Chart
+		if instance.Spec.DryRun {
+			if err = r.dryRunHelmChartAddon(ctx, logger, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+			break
+		}
+
+		if instance.Status.DryRun != nil {
+			// the addon is no longer a dry run, so the result of the last one is stale
+			patch := client.MergeFrom(instance.DeepCopy())
+			instance.Status.DryRun = nil
+			if err = r.Status().Patch(ctx, instance, patch); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if err = r.startChartOperation(ctx, logger, instance); err != nil {
+			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "Failed to Create Chart Addon %s/%s : %s", instance.Spec.Namespace, instance.Name, err)
+			return ctrl.Result{}, err
+		}
+
+		if helm.OperationUninstalled(instance) {
+			// the release was uninstalled after timing out, it is only installed again once the addon spec changes
+			logger.Info("Helm Chart uninstalled as per the failure policy", "Name", chart.Name, "Version", chart.Version)
+			err = r.updateStatus(ctx, logger, req.NamespacedName, v1alpha1.TypeComponentUnhealthy, fmt.Sprintf("Helm Chart %s uninstalled after timing out", instance.Spec.Name), instance.Status.Operation.LastFailure)
+			return ctrl.Result{}, err
+		}
+
 		logger.Info("Creating Addon HelmChart resource", "Name", chart.Name, "Version", chart.Version)
-		if err = r.helmController.CreateHelmRelease(ctx, instance, instance.Spec.Namespace, instance.Spec.DryRun); err != nil {
+		if err = r.helmController.CreateHelmRelease(ctx, instance, instance.Spec.Namespace); err != nil {
 			logger.Error(err, "failed to install addon", "Name", chart.Name, "Version", chart.Version)
 			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "Failed to Create Chart Addon %s/%s : %s", instance.Spec.Namespace, instance.Name, err)
 			return ctrl.Result{}, err
@@ -166,11 +221,22 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 			return ctrl.Result{}, err
 		}
 
-		if err = r.updateHelmChartAddonStatus(ctx, logger, req.NamespacedName, release, instance); err != nil {
+		var sourceFailure string
+		if sourceFailure, err = r.getChartSourceFailure(ctx, instance); err != nil {
+			logger.Error(err, "Failed to get Helm Chart sources", "Name", releaseName)
+			return ctrl.Result{}, err
+		}
+
+		if err = r.updateHelmChartAddonStatus(ctx, logger, req.NamespacedName, release, instance, sourceFailure); err != nil {
 			logger.Error(err, "Failed to update Helm Chart Addon status", "Name", releaseName)
 			return ctrl.Result{}, err
 		}
 
+		// enforce the timeout and failure policy of the last install or upgrade
+		var result ctrl.Result
+		result, err = r.reconcileChartOperation(ctx, logger, instance, release, sourceFailure)
+		return result, err
+
 	case kindManifest:
 		if err = r.manifestController.CreateManifest(ctx, consts.NamespaceBlueprintSystem, instance.Spec.Name, instance.Spec.Manifest); err != nil {
 			logger.Error(err, "failed to install addon via manifest", "URL", instance.Spec.Manifest.URL)
@@ -204,23 +270,27 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	return ctrl.Result{}, nil
 }
 
-func (r *AddonReconciler) deleteAddon(ctx context.Context, addon *v1alpha1.Addon) error {
+// deleteAddon deletes the helm release or manifest of the addon and returns whether it is gone
+func (r *AddonReconciler) deleteAddon(ctx context.Context, addon *v1alpha1.Addon) (bool, error) {
 	switch addon.Spec.Kind {
 	case kindChart:
-		if err := r.helmController.DeleteHelmRelease(ctx, addon); err != nil {
+		deleted, err := r.helmController.DeleteHelmRelease(ctx, addon)
+		if err != nil {
 			r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedDelete, "Failed to Delete Chart Addon %s/%s: %s", addon.Spec.Namespace, addon.Name, err)
-			return err
+			return false, err
 		}
+		return deleted, nil
 	case kindManifest:
-		// our finalizer is present, so lets delete the helm chart
-		if err := r.manifestController.DeleteManifest(ctx, consts.NamespaceBlueprintSystem, addon.Spec.Name, addon.Spec.Manifest.URL); err != nil {
+		// our finalizer is present, so lets delete the manifest
+		deleted, err := r.manifestController.DeleteManifest(ctx, consts.NamespaceBlueprintSystem, addon.Spec.Name, addon.Spec.Manifest.URL)
+		if err != nil {
 			r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedDelete, "Failed to Delete Manifest Addon %s/%s : %s", addon.Spec.Namespace, addon.Name, err)
-			return err
+			return false, err
 		}
+		return deleted, nil
 	default:
-		return fmt.Errorf("invalid addon kind: %s", addon.Spec.Kind)
+		return false, fmt.Errorf("invalid addon kind: %s", addon.Spec.Kind)
 	}
-	return nil
 }
 
 // updateManifestAddonStatus checks if the manifest associated with the addon has a status to bubble up to addon and updates addon if so
@@ -243,7 +313,24 @@ func (r *AddonReconciler) updateManifestAddonStatus(ctx context.Context, logger
 	if err != nil {
 		return err
 	}
-	return nil
+	return r.updateFailedObjects(ctx, logger, types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name}, manifest.Status.Failures)
+}
+
+// updateFailedObjects sets the objects of the manifest that failed to be applied in the status of the addon
+func (r *AddonReconciler) updateFailedObjects(ctx context.Context, logger logr.Logger, namespacedName types.NamespacedName, failures []v1alpha1.ManifestObjectFailure) error {
+	addon := &v1alpha1.Addon{}
+	if err := r.Get(ctx, namespacedName, addon); err != nil {
+		logger.Error(err, "Failed to get addon to update failed objects")
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(addon.Status.FailedObjects, failures) {
+		return nil
+	}
+
+	patch := client.MergeFrom(addon.DeepCopy())
+	addon.Status.FailedObjects = failures
+	return r.Status().Patch(ctx, addon, patch)
 }
 
 // setOwnerReferenceOnManifest sets the owner reference on the manifest object to point to the addon object
@@ -263,20 +350,124 @@ func (r *AddonReconciler) setOwnerReferenceOnManifest(ctx context.Context, logge
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AddonReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.restConfig = mgr.GetConfig()
+
+	// attaches an index onto the Addon
+	// This is done, so we can later easily find the addons referencing a particular ConfigMap or Secret for values
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Addon{}, addonValuesFromIndex, indexAddonValuesFrom); err != nil {
+		return err
+	}
+
+	// attaches an index onto the Addon
+	// This is done, so we can later easily find the addons whose chart is fetched from a particular source
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Addon{}, addonSourceIndex, indexAddonSources); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Addon{}).
 		Owns(&v1alpha1.Manifest{}).
 		Owns(&helmv2.HelmRelease{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findAddons(addonValuesFromIndex, kindConfigMap)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findAddons(addonValuesFromIndex, kindSecret)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&sourcev1.HelmRepository{},
+			handler.EnqueueRequestsFromMapFunc(r.findAddons(addonSourceIndex, helm.KindHelmRepository)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&sourcev1.HelmChart{},
+			handler.EnqueueRequestsFromMapFunc(r.findAddons(addonSourceIndex, helm.KindHelmChart)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Complete(r)
 }
 
+// indexAddonValuesFrom returns the addonValuesFromIndex keys of the ConfigMaps and Secrets referenced by an addon
+func indexAddonValuesFrom(rawObj client.Object) []string {
+	addon := rawObj.(*v1alpha1.Addon)
+	if addon.Spec.Chart == nil {
+		return nil
+	}
+
+	var indexes []string
+	for _, ref := range addon.Spec.Chart.ValuesFrom {
+		indexes = append(indexes, fmt.Sprintf("%s/%s", ref.Kind, ref.Name))
+	}
+	return indexes
+}
+
+// indexAddonSources returns the addonSourceIndex keys of the HelmRepository and HelmChart objects of a chart addon
+func indexAddonSources(rawObj client.Object) []string {
+	addon := rawObj.(*v1alpha1.Addon)
+	if addon.Spec.Chart == nil {
+		return nil
+	}
+
+	return []string{
+		fmt.Sprintf("%s/%s", helm.KindHelmRepository, helm.RepoName(addon)),
+		fmt.Sprintf("%s/%s", helm.KindHelmChart, helm.HelmChartName(addon)),
+	}
+}
+
+// findAddons returns a function finding the addons referencing an object of the provided kind in the provided index
+// Only objects of the blueprint-system namespace can be referenced.
+func (r *AddonReconciler) findAddons(index, kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if obj.GetNamespace() != consts.NamespaceBlueprintSystem {
+			return nil
+		}
+
+		addons := &v1alpha1.AddonList{}
+		if err := r.List(ctx, addons, client.MatchingFields{index: fmt.Sprintf("%s/%s", kind, obj.GetName())}); err != nil {
+			return []reconcile.Request{}
+		}
+
+		requests := make([]reconcile.Request, len(addons.Items))
+		for i, item := range addons.Items {
+			requests[i] = reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.GetName(),
+					Namespace: item.GetNamespace(),
+				},
+			}
+		}
+		return requests
+	}
+}
+
 // updateHelmChartAddonStatus checks the status of the associated helm release and updates the status of the Addon CR accordingly
-func (r *AddonReconciler) updateHelmChartAddonStatus(ctx context.Context, logger logr.Logger, namespacedName types.NamespacedName, release *helmv2.HelmRelease, addon *v1alpha1.Addon) error {
+// A failure of the HelmRepository or HelmChart the chart is fetched from takes precedence over the release
+// status, since the helm-controller keeps waiting for the chart in that case.
+func (r *AddonReconciler) updateHelmChartAddonStatus(ctx context.Context, logger logr.Logger, namespacedName types.NamespacedName, release *helmv2.HelmRelease, addon *v1alpha1.Addon, sourceFailure string) error {
 	logger.Info("Updating Helm Chart Addon Status")
 	releaseStatus := helm.DetermineReleaseStatus(release)
 	if releaseStatus == helm.ReleaseStatusSuccess {
 		r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeNormal, event.ReasonSuccessfulCreate, "Created Chart Addon %s/%s", addon.Spec.Namespace, addon.Name)
-		err := r.updateStatus(ctx, logger, namespacedName, v1alpha1.TypeComponentAvailable, fmt.Sprintf("Helm Chart %s successfully installed", release.Name))
+		// the message of an earlier failure, e.g. of the chart source, no longer applies
+		err := r.updateStatus(ctx, logger, namespacedName, v1alpha1.TypeComponentAvailable, fmt.Sprintf("Helm Chart %s successfully installed", release.Name), "")
+		if err != nil {
+			return err
+		}
+	} else if helm.OperationFailed(addon) {
+		// the release timed out and was left as is, it stays unhealthy until it becomes ready
+		err := r.updateStatus(ctx, logger, namespacedName, v1alpha1.TypeComponentUnhealthy, fmt.Sprintf("Helm Chart %s timed out", release.Name), addon.Status.Operation.LastFailure)
+		if err != nil {
+			return err
+		}
+	} else if sourceFailure != "" {
+		if addon.Status.Message != sourceFailure {
+			r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedCreate, "Helm Chart Addon %s/%s source is not ready: %s", addon.Spec.Namespace, addon.Name, sourceFailure)
+		}
+		err := r.updateStatus(ctx, logger, namespacedName, v1alpha1.TypeComponentUnhealthy, fmt.Sprintf("Helm Chart %s source is not ready", release.Name), sourceFailure)
 		if err != nil {
 			return err
 		}
@@ -295,6 +486,194 @@ func (r *AddonReconciler) updateHelmChartAddonStatus(ctx context.Context, logger
 	return nil
 }
 
+// getChartSourceFailure gets the HelmRepository and HelmChart the chart of the addon is fetched from and describes
+// why they aren't ready. It is empty if they are ready or don't exist yet.
+func (r *AddonReconciler) getChartSourceFailure(ctx context.Context, addon *v1alpha1.Addon) (string, error) {
+	repo := &sourcev1.HelmRepository{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: helm.RepoName(addon)}, repo); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		repo = nil
+	}
+
+	chart := &sourcev1.HelmChart{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: helm.HelmChartName(addon)}, chart); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		chart = nil
+	}
+
+	return helm.SourceFailure(repo, chart), nil
+}
+
+// startChartOperation records a new operation in the status of a chart addon with a timeout whenever its spec changes
+// The operation of a chart addon without a timeout is removed.
+func (r *AddonReconciler) startChartOperation(ctx context.Context, logger logr.Logger, addon *v1alpha1.Addon) error {
+	op := addon.Status.Operation
+	if addon.Spec.Chart.Timeout == "" {
+		if op == nil {
+			return nil
+		}
+		return r.updateChartOperation(ctx, addon, nil)
+	}
+
+	if op != nil && op.Generation == addon.Generation {
+		return nil
+	}
+
+	op, err := helm.NewOperation(addon, 1, time.Now())
+	if err != nil {
+		logger.Error(err, "failed to parse timeout for chart", "Timeout", addon.Spec.Chart.Timeout)
+		return err
+	}
+	logger.Info("chart spec changed, starting operation", "Generation", addon.Generation, "Deadline", op.Deadline)
+	return r.updateChartOperation(ctx, addon, op)
+}
+
+// reconcileChartOperation enforces the timeout and failure policy of the operation recorded in the status of a chart addon
+// Since the operation and its deadline are persisted, the timeout is enforced by requeueing the addon
+// at the deadline, which survives operator restarts and leader changes.
+func (r *AddonReconciler) reconcileChartOperation(ctx context.Context, logger logr.Logger, addon *v1alpha1.Addon, release *helmv2.HelmRelease, sourceFailure string) (ctrl.Result, error) {
+	now := time.Now()
+	action, remaining := helm.CheckOperation(addon, helm.DetermineReleaseStatus(release), now)
+	op := addon.Status.Operation.DeepCopy()
+
+	switch action {
+	case helm.OperationActionWait:
+		logger.Info("chart operation in progress", "Attempts", op.Attempts, "Deadline", op.Deadline)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+
+	case helm.OperationActionComplete:
+		logger.Info("chart operation completed", "Attempts", op.Attempts)
+		completionTime := metav1.NewTime(now)
+		op.CompletionTime = &completionTime
+		return ctrl.Result{}, r.updateChartOperation(ctx, addon, op)
+	}
+
+	if action == helm.OperationActionNone {
+		return ctrl.Result{}, nil
+	}
+
+	// the operation timed out
+	failure := fmt.Sprintf("release not ready after %s", op.Deadline.Sub(op.StartTime.Time))
+	if sourceFailure != "" {
+		failure = fmt.Sprintf("%s: %s", failure, sourceFailure)
+	} else if reason := helm.FailureReason(release); reason != "" {
+		failure = fmt.Sprintf("%s: %s", failure, reason)
+	}
+	logger.Info("chart operation timed out", "Attempts", op.Attempts, "FailurePolicy", addon.Spec.Chart.FailurePolicy, "Failure", failure)
+	r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedCreate, "Helm Chart Addon %s/%s : %s", addon.Spec.Namespace, addon.Name, failure)
+	if err := r.updateStatus(ctx, logger, types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name}, v1alpha1.TypeComponentUnhealthy, fmt.Sprintf("Helm Chart %s timed out", release.Name), failure); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	failureTime := metav1.NewTime(now)
+	switch action {
+	case helm.OperationActionFail:
+		op.LastFailure = failure
+		op.LastFailureTime = &failureTime
+		op.CompletionTime = &failureTime
+		return ctrl.Result{}, r.updateChartOperation(ctx, addon, op)
+
+	case helm.OperationActionUninstall:
+		logger.Info("Uninstalling helm release", "Name", release.Name)
+		if err := r.helmController.UninstallHelmRelease(ctx, addon); err != nil {
+			r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedDelete, "Failed to Uninstall Chart Addon %s/%s : %s", addon.Spec.Namespace, addon.Name, err)
+			return ctrl.Result{}, err
+		}
+		op.LastFailure = failure
+		op.LastFailureTime = &failureTime
+		op.CompletionTime = &failureTime
+		op.Uninstalled = true
+		return ctrl.Result{}, r.updateChartOperation(ctx, addon, op)
+
+	case helm.OperationActionRetry:
+		next, err := helm.NewOperation(addon, op.Attempts+1, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		next.LastFailure = failure
+		next.LastFailureTime = &failureTime
+		if err = r.updateChartOperation(ctx, addon, next); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// the new attempt in the status of the addon makes the HelmRelease reset and forced
+		logger.Info("Retrying helm release", "Name", release.Name, "Attempts", next.Attempts)
+		if err = r.helmController.CreateHelmRelease(ctx, addon, addon.Spec.Namespace); err != nil {
+			r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedCreate, "Failed to Retry Chart Addon %s/%s : %s", addon.Spec.Namespace, addon.Name, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Until(next.Deadline.Time)}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// updateChartOperation sets the operation in the status of the chart addon
+// The provided addon is updated with the patched status.
+func (r *AddonReconciler) updateChartOperation(ctx context.Context, addon *v1alpha1.Addon, op *v1alpha1.ChartOperation) error {
+	latest := &v1alpha1.Addon{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name}, latest); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(latest.DeepCopy())
+	latest.Status.Operation = op
+	if err := r.Status().Patch(ctx, latest, patch); err != nil {
+		return err
+	}
+
+	addon.Status.Operation = op
+	return nil
+}
+
+// dryRunHelmChartAddon renders the chart of the addon without installing it and records the result in the addon status
+// The chart is only rendered again when the addon spec changes. An existing HelmRelease is left untouched.
+func (r *AddonReconciler) dryRunHelmChartAddon(ctx context.Context, logger logr.Logger, addon *v1alpha1.Addon) error {
+	if addon.Status.DryRun != nil && addon.Status.DryRun.ObservedGeneration == addon.Generation {
+		logger.Info("Dry run result is up to date", "Name", addon.Name, "Generation", addon.Generation)
+		return nil
+	}
+
+	logger.Info("Dry run of Addon HelmChart", "Name", addon.Spec.Chart.Name, "Version", addon.Spec.Chart.Version)
+	result, err := r.helmController.DryRunHelmRelease(ctx, addon, addon.Spec.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to dry run addon", "Name", addon.Spec.Chart.Name, "Version", addon.Spec.Chart.Version)
+		r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedDryRun, "Failed to Dry Run Chart Addon %s/%s : %s", addon.Spec.Namespace, addon.Name, err)
+		if statusErr := r.updateStatus(ctx, logger, types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name}, v1alpha1.TypeComponentUnhealthy, "Dry run failed", err.Error()); statusErr != nil {
+			logger.Error(statusErr, "Failed to update addon status")
+		}
+		return err
+	}
+
+	status := v1alpha1.Status{
+		Type:               v1alpha1.TypeComponentDryRun,
+		Reason:             "Dry run succeeded",
+		Message:            fmt.Sprintf("Helm Chart %s rendered %d objects", addon.Spec.Chart.Name, len(result.Objects)),
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(result.Errors) > 0 {
+		status.Type = v1alpha1.TypeComponentUnhealthy
+		status.Reason = "Dry run found errors"
+		status.Message = fmt.Sprintf("Helm Chart %s has %d errors, see status.dryRun.errors", addon.Spec.Chart.Name, len(result.Errors))
+		r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeWarning, event.ReasonFailedDryRun, "Dry Run of Chart Addon %s/%s found errors: %s", addon.Spec.Namespace, addon.Name, strings.Join(result.Errors, "; "))
+	} else {
+		r.Recorder.AnnotatedEventf(addon, map[string]string{event.AddonAnnotationKey: addon.Name}, event.TypeNormal, event.ReasonSuccessfulDryRun, "Dry Run of Chart Addon %s/%s succeeded", addon.Spec.Namespace, addon.Name)
+	}
+
+	patch := client.MergeFrom(addon.DeepCopy())
+	if addon.Status.Type == status.Type {
+		status.LastTransitionTime = addon.Status.LastTransitionTime
+	}
+	addon.Status.Status = status
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.DryRun = result
+	return r.Status().Patch(ctx, addon, patch)
+}
+
 // updateStatus queries for a fresh Addon with the provided namespacedName.
 // This avoids some errors where we fail to update status because we have an older (stale) version of the object
 // It then updates the Addon's status fields with the provided type, reason, and optionally message.
@@ -308,8 +687,9 @@ func (r *AddonReconciler) updateStatus(ctx context.Context, logger logr.Logger,
 		return err
 	}
 
-	nilStatus := v1alpha1.AddonStatus{}
-	if addon.Status != nilStatus && addon.Status.Type == typeToApply && addon.Status.Reason == reasonToApply {
+	nilStatus := v1alpha1.Status{}
+	sameMessage := len(messageToApply) == 0 || addon.Status.Message == messageToApply[0]
+	if addon.Status.Status != nilStatus && addon.Status.Type == typeToApply && addon.Status.Reason == reasonToApply && sameMessage && addon.Status.ObservedGeneration == addon.Generation {
 		// avoid infinite reconciliation loops
 		logger.Info("No updates to status needed")
 		return nil
@@ -318,12 +698,15 @@ func (r *AddonReconciler) updateStatus(ctx context.Context, logger logr.Logger,
 	logger.Info("Update status for addon", "Name", addon.Name)
 
 	patch := client.MergeFrom(addon.DeepCopy())
+	if addon.Status.Type != typeToApply || addon.Status.Reason != reasonToApply {
+		addon.Status.LastTransitionTime = metav1.Now()
+	}
 	addon.Status.Type = typeToApply
 	addon.Status.Reason = reasonToApply
 	if len(messageToApply) > 0 {
 		addon.Status.Message = messageToApply[0]
 	}
-	addon.Status.LastTransitionTime = metav1.Now()
+	addon.Status.ObservedGeneration = addon.Generation
 
 	return r.Status().Patch(ctx, addon, patch)
 }
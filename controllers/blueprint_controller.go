@@ -10,9 +10,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
@@ -31,6 +37,9 @@ type BlueprintReconciler struct {
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=blueprints,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=blueprints/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=blueprints/finalizers,verbs=update
+//+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=addons,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=manifests,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=issuers;clusterissuers;certificates,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -54,81 +63,184 @@ func (r *BlueprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	err := r.reconcileAddons(ctx, logger, instance)
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, logger, instance)
+	}
+
+	if !controllerutil.ContainsFinalizer(instance, blueprintFinalizer) {
+		controllerutil.AddFinalizer(instance, blueprintFinalizer)
+		if err := r.Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	waiting, reconcileErr := r.reconcileComponents(ctx, logger, instance)
+
+	summary, err := r.collectBlueprintStatus(ctx, instance, waiting)
 	if err != nil {
+		logger.Error(err, "Failed to collect blueprint status", "Name", req.Name)
 		return ctrl.Result{}, err
 	}
 
-	err = reconcileObjects(ctx, logger, r.Client,
+	if err = r.updateStatus(ctx, logger, instance, summary, reconcileErr); err != nil {
+		logger.Error(err, "Failed to update blueprint status", "Name", req.Name)
+		return ctrl.Result{}, err
+	}
+
+	if reconcileErr != nil {
+		return ctrl.Result{}, reconcileErr
+	}
+
+	// Addons are watched, but cert-manager resources are not, so we need to poll for their status
+	if !summary.resourcesReady() {
+		logger.Info("Blueprint resources are not yet ready", "Name", req.Name, "Requeue", true)
+		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDeletion removes the addons and resources of a deleted blueprint as per its deletion policy
+// The finalizer is removed once they are all gone. Until then, the progress is reported in the blueprint status.
+func (r *BlueprintReconciler) reconcileDeletion(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, blueprintFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Blueprint is being deleted", "Name", instance.Name, "DeletionPolicy", instance.Spec.DeletionPolicy)
+	remaining, err := r.finalizeBlueprint(ctx, logger, instance)
+	if err != nil || len(remaining) > 0 {
+		if statusErr := r.updateDeletionStatus(ctx, instance, remaining, err); statusErr != nil {
+			logger.Error(statusErr, "Failed to update blueprint status", "Name", instance.Name)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to remove blueprint components", "Name", instance.Name)
+			return ctrl.Result{}, err
+		}
+		logger.Info("Waiting for addons to be uninstalled", "Name", instance.Name, "Addons", remaining, "Requeue", true)
+		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+	}
+
+	controllerutil.RemoveFinalizer(instance, blueprintFinalizer)
+	if err = r.Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileComponents creates, updates or deletes the addons and resources declared in the blueprint
+// It returns the addons that are waiting for their dependencies, along with the pending dependencies.
+func (r *BlueprintReconciler) reconcileComponents(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint) (map[string][]string, error) {
+	waiting, err := r.reconcileAddons(ctx, logger, instance)
+	if err != nil {
+		return waiting, err
+	}
+
+	err = reconcileObjects(ctx, logger, r.Client, instance,
 		convertToObjects(instance.Spec.Resources.CertManagement.Issuers, issuerObject), listIssuers)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to reconcile Issuers: %w", err)
+		return waiting, fmt.Errorf("unable to reconcile Issuers: %w", err)
 	}
 
-	err = reconcileObjects(ctx, logger, r.Client,
+	err = reconcileObjects(ctx, logger, r.Client, instance,
 		convertToObjects(instance.Spec.Resources.CertManagement.ClusterIssuers, clusterIssuerObject), listClusterIssuers)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to reconcile ClusterIssuers: %w", err)
+		return waiting, fmt.Errorf("unable to reconcile ClusterIssuers: %w", err)
 	}
 
-	err = reconcileObjects(ctx, logger, r.Client,
+	err = reconcileObjects(ctx, logger, r.Client, instance,
 		convertToObjects(instance.Spec.Resources.CertManagement.Certificates, certificateObject), listCertificates)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to reconcile Resources: %w", err)
+		return waiting, fmt.Errorf("unable to reconcile Resources: %w", err)
 	}
 
-	return ctrl.Result{}, nil
+	return waiting, nil
 }
 
-func (r *BlueprintReconciler) reconcileAddons(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint) error {
-	addonsToUninstall, err := r.getInstalledAddons(ctx, logger)
+// reconcileAddons creates or updates the enabled addons in dependency order and deletes the addons that are no
+// longer part of the blueprint. An addon is only created or updated once all of its dependencies are available.
+func (r *BlueprintReconciler) reconcileAddons(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint) (map[string][]string, error) {
+	waiting := map[string][]string{}
+
+	addonsToUninstall, err := r.getInstalledAddons(ctx, logger, instance)
 	if err != nil {
-		return err
+		return waiting, err
 	}
 
-	for _, addonSpec := range instance.Spec.Components.Addons {
+	addons, err := sortAddonsByDependencies(instance.Spec.Components.Addons)
+	if err != nil {
+		return waiting, err
+	}
+
+	// addons that can't be depended upon yet, because they are waiting themselves or have just been changed
+	notReady := map[string]bool{}
+	for _, addonSpec := range addons {
 		if addonSpec.Namespace == "" {
 			addonSpec.Namespace = instance.Namespace
 		}
 
-		if !addonSpec.Enabled {
-			// No create/update the addon if it is not enabled
+		// if the addon is in the spec , we shouldn't uninstall it
+		delete(addonsToUninstall, addonSpec.Name)
+
+		pending, err := r.pendingDependencies(ctx, addonSpec, notReady)
+		if err != nil {
+			return waiting, err
+		}
+		if len(pending) > 0 {
+			logger.Info("Addon is waiting for dependencies", "Name", addonSpec.Name, "Dependencies", pending)
+			waiting[addonSpec.Name] = pending
+			notReady[addonSpec.Name] = true
 			continue
 		}
 
 		logger.Info("Reconciling addonSpec", "Name", addonSpec.Name, "Spec.Namespace", addonSpec.Namespace)
 		addon := addonResource(&addonSpec)
-		err = r.createOrUpdateAddon(ctx, logger, addon)
+		setOwner(addon, instance)
+		changed, err := r.createOrUpdateAddon(ctx, logger, addon)
 		if err != nil {
 			logger.Error(err, "Failed to reconcile addonSpec", "Name", addonSpec.Name, "Spec.Namespace", addonSpec.Namespace)
-			return err
+			return waiting, err
+		}
+		if changed {
+			notReady[addonSpec.Name] = true
 		}
-
-		// if the addon is in the spec , we shouldn't uninstall it
-		delete(addonsToUninstall, addon.GetName())
 	}
 
 	if len(addonsToUninstall) > 0 {
 		err = r.deleteAddons(ctx, logger, addonsToUninstall)
 		if err != nil {
-			return err
+			return waiting, err
 		}
 	}
 
-	return nil
+	return waiting, nil
 }
 
-// getInstalledAddons returns a map of addons that are presently installed in the cluster
-func (r *BlueprintReconciler) getInstalledAddons(ctx context.Context, logger logr.Logger) (map[string]v1alpha1.Addon, error) {
+// getInstalledAddons returns a map of addons owned by the blueprint that are presently installed in the cluster
+// Addons created by a previous version of the operator, which are not owned by any blueprint, are adopted.
+func (r *BlueprintReconciler) getInstalledAddons(ctx context.Context, logger logr.Logger, owner *v1alpha1.Blueprint) (map[string]v1alpha1.Addon, error) {
 	allAddonsInCluster := &v1alpha1.AddonList{}
-	if err := r.List(ctx, allAddonsInCluster); err != nil {
+	if err := r.List(ctx, allAddonsInCluster, managedByBOPSelector); err != nil {
 		return nil, err
 	}
 
 	logger.Info("existing addons are", "addonNames", allAddonsInCluster.Items)
 	addonsToUninstall := make(map[string]v1alpha1.Addon)
-	for _, addon := range allAddonsInCluster.Items {
-		addonsToUninstall[addon.GetName()] = addon
+	var unowned []client.Object
+	for i, addon := range allAddonsInCluster.Items {
+		if isOwnedBy(&addon, owner) {
+			addonsToUninstall[addon.GetName()] = addon
+		} else if _, ok := ownerOf(&addon); !ok {
+			unowned = append(unowned, &allAddonsInCluster.Items[i])
+		}
+	}
+
+	adopted, err := adoptLegacyObjects(ctx, logger, r.Client, owner, unowned)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range adopted {
+		addonsToUninstall[obj.GetName()] = *obj.(*v1alpha1.Addon)
 	}
 
 	return addonsToUninstall, nil
@@ -147,15 +259,21 @@ func (r *BlueprintReconciler) deleteAddons(ctx context.Context, logger logr.Logg
 	return nil
 }
 
-func (r *BlueprintReconciler) createOrUpdateAddon(ctx context.Context, logger logr.Logger, addon *v1alpha1.Addon) error {
+// createOrUpdateAddon creates the addon or updates the existing one
+// It returns true if the addon was created or its spec has changed.
+func (r *BlueprintReconciler) createOrUpdateAddon(ctx context.Context, logger logr.Logger, addon *v1alpha1.Addon) (bool, error) {
 	existing := &v1alpha1.Addon{}
 	if err := r.Get(ctx, client.ObjectKey{Name: addon.GetName(), Namespace: addon.GetNamespace()}, existing); err != nil {
 		if client.IgnoreNotFound(err) != nil {
-			return err
+			return false, err
 		}
 	}
 
 	if existing.Name != "" {
+		if err := checkOwner(existing, addon); err != nil {
+			return false, err
+		}
+
 		logger.Info("Add-on already exists. Updating", "Name", existing.Name, "Spec.Namespace", existing.Spec.Namespace)
 
 		if existing.Spec.Namespace == addon.Spec.Namespace {
@@ -163,9 +281,9 @@ func (r *BlueprintReconciler) createOrUpdateAddon(ctx context.Context, logger lo
 			// TODO : Copy all the fields from the existing
 			addon.SetFinalizers(existing.GetFinalizers())
 			if err := r.Update(ctx, addon); err != nil {
-				return fmt.Errorf("failed to update add-on %s: %w", existing.Name, err)
+				return false, fmt.Errorf("failed to update add-on %s: %w", existing.Name, err)
 			}
-			return nil
+			return addon.GetGeneration() != existing.GetGeneration(), nil
 		} else {
 			// the addon spec has moved namespaces, we need to delete and re-create it
 			logger.Info("Addon has moved namespaces, deleting old version of add on",
@@ -174,16 +292,16 @@ func (r *BlueprintReconciler) createOrUpdateAddon(ctx context.Context, logger lo
 				"New Namespace", addon.Spec.Namespace)
 			if err := r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
 				logger.Error(err, "Failed to remove old version of addon", "Name", existing.Name)
-				return err
+				return false, err
 			}
 		}
 	}
 
 	logger.Info("Creating add-on", "Name", addon.GetName(), "Spec.Namespace", addon.Spec.Namespace)
 	if err := r.Create(ctx, addon); err != nil {
-		return fmt.Errorf("failed to create add-on %s: %w", addon.GetName(), err)
+		return false, fmt.Errorf("failed to create add-on %s: %w", addon.GetName(), err)
 	}
-	return nil
+	return true, nil
 }
 
 func addonResource(spec *v1alpha1.AddonSpec) *v1alpha1.Addon {
@@ -191,12 +309,16 @@ func addonResource(spec *v1alpha1.AddonSpec) *v1alpha1.Addon {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      spec.Name,
 			Namespace: consts.NamespaceBlueprintSystem,
+			Labels: map[string]string{
+				consts.ManagedByLabel: consts.ManagedByValue,
+			},
 		},
 		Spec: v1alpha1.AddonSpec{
 			Name:      spec.Name,
 			Namespace: spec.Namespace,
 			Kind:      spec.Kind,
 			DryRun:    spec.DryRun,
+			DependsOn: spec.DependsOn,
 		},
 	}
 
@@ -208,6 +330,18 @@ func addonResource(spec *v1alpha1.AddonSpec) *v1alpha1.Addon {
 			Set:       spec.Chart.Set,
 			Values:    spec.Chart.Values,
 			DependsOn: spec.Chart.DependsOn,
+
+			ValuesFrom:      spec.Chart.ValuesFrom,
+			HelmOptions:     spec.Chart.HelmOptions,
+			Kustomize:       spec.Chart.Kustomize,
+			SecretRef:       spec.Chart.SecretRef,
+			CertSecretRef:   spec.Chart.CertSecretRef,
+			PassCredentials: spec.Chart.PassCredentials,
+			Insecure:        spec.Chart.Insecure,
+
+			FailurePolicy: spec.Chart.FailurePolicy,
+			MaxRetries:    spec.Chart.MaxRetries,
+			Timeout:       spec.Chart.Timeout,
 		}
 	}
 
@@ -327,6 +461,52 @@ func convertToObjects[T any](items []T, converter func(T) client.Object) []clien
 // SetupWithManager sets up the controller with the Manager.
 func (r *BlueprintReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Blueprint{}).
+		// status updates made by this controller should not trigger a new reconcile
+		For(&v1alpha1.Blueprint{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&v1alpha1.Addon{},
+			handler.EnqueueRequestsFromMapFunc(r.findBlueprintsForObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&v1alpha1.Manifest{},
+			handler.EnqueueRequestsFromMapFunc(r.findBlueprintsForObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Complete(r)
 }
+
+// findBlueprintsForObject returns a reconcile request for the blueprint owning the addon or manifest
+// so that its status is rolled up into the blueprint status. Objects without an owner, such as orphaned addons,
+// trigger a reconcile of every blueprint in the cluster.
+func (r *BlueprintReconciler) findBlueprintsForObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	if _, ok := obj.(*v1alpha1.Manifest); ok {
+		addon, err := r.getOwningAddon(ctx, obj)
+		if client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "Failed to get addon of manifest", "Manifest", obj.GetName())
+		}
+		if err == nil {
+			obj = addon
+		}
+	}
+	if owner, ok := ownerOf(obj); ok {
+		return []reconcile.Request{{NamespacedName: owner}}
+	}
+
+	blueprints := &v1alpha1.BlueprintList{}
+	if err := r.List(ctx, blueprints); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list blueprints", "Object", obj.GetName())
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(blueprints.Items))
+	for i, item := range blueprints.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
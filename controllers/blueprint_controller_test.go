@@ -2,13 +2,23 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	v1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
@@ -51,6 +61,9 @@ var _ = Describe("Blueprint controller", Ordered, Serial, func() {
 		// Reset the state by deleting the blueprint
 		blueprint := newBlueprint()
 		Expect(k8sClient.Delete(ctx, blueprint)).Should(Succeed())
+
+		// the blueprint is only removed once its finalizer has uninstalled its addons
+		Eventually(getObject(ctx, blueprintLookupKey, &v1alpha1.Blueprint{}), timeoutOneMinute, defaultInterval).Should(BeFalse())
 	})
 
 	Context("A blueprint is created", func() {
@@ -211,3 +224,512 @@ var _ = Describe("Object operations", func() {
 		})
 	})
 })
+
+var _ = Describe("Blueprint status", func() {
+	var chartAddon, manifestAddon v1alpha1.AddonSpec
+
+	BeforeEach(func() {
+		chartAddon = v1alpha1.AddonSpec{Name: "chart-addon", Namespace: "ns1", Kind: "chart", Enabled: true}
+		manifestAddon = v1alpha1.AddonSpec{Name: "manifest-addon", Namespace: "ns2", Kind: "manifest", Enabled: true}
+	})
+
+	addonWithStatus := func(name string, statusType v1alpha1.StatusType) *v1alpha1.Addon {
+		return &v1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: consts.NamespaceBlueprintSystem},
+			Status:     v1alpha1.AddonStatus{Status: v1alpha1.Status{Type: statusType, Reason: "test"}},
+		}
+	}
+
+	It("summarizes addons that have not been created yet as progressing", func(ctx context.Context) {
+		r := &BlueprintReconciler{Client: fake.NewClientBuilder().Build()}
+
+		summary, err := r.collectBlueprintStatus(ctx, newBlueprint(chartAddon), nil)
+		Expect(err).To(BeNil())
+		Expect(summary.addons).To(HaveLen(1))
+		Expect(summary.addons[0].Name).To(Equal(chartAddon.Name))
+		Expect(summary.addons[0].Type).To(Equal(v1alpha1.TypeComponentProgressing))
+		Expect(summary.ready()).To(BeFalse())
+	})
+
+	It("skips disabled addons", func(ctx context.Context) {
+		r := &BlueprintReconciler{Client: fake.NewClientBuilder().Build()}
+		chartAddon.Enabled = false
+
+		summary, err := r.collectBlueprintStatus(ctx, newBlueprint(chartAddon), nil)
+		Expect(err).To(BeNil())
+		Expect(summary.addons).To(BeEmpty())
+		Expect(summary.ready()).To(BeTrue())
+	})
+
+	It("is ready when all addons and resources are available", func(ctx context.Context) {
+		issuer := issuerObject(v1alpha1.Issuer{Name: "issuer1", Namespace: "ns1"}).(*v1.Issuer)
+		issuer.Status.Conditions = []v1.IssuerCondition{{Type: v1.IssuerConditionReady, Status: cmmeta.ConditionTrue}}
+
+		fakeClient := fake.NewClientBuilder().WithObjects(
+			addonWithStatus(chartAddon.Name, v1alpha1.TypeComponentAvailable),
+			addonWithStatus(manifestAddon.Name, v1alpha1.TypeComponentAvailable),
+			issuer,
+		).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		blueprint := newBlueprint(chartAddon, manifestAddon)
+		blueprint.Spec.Resources.CertManagement.Issuers = []v1alpha1.Issuer{{Name: "issuer1", Namespace: "ns1"}}
+
+		summary, err := r.collectBlueprintStatus(ctx, blueprint, nil)
+		Expect(err).To(BeNil())
+		Expect(summary.addons).To(HaveLen(2))
+		Expect(summary.resources).To(HaveLen(1))
+		Expect(summary.resources[0].Type).To(Equal(v1alpha1.TypeComponentAvailable))
+		Expect(summary.ready()).To(BeTrue())
+
+		setBlueprintConditions(&blueprint.Status, summary, nil)
+		Expect(meta.IsStatusConditionTrue(blueprint.Status.Conditions, string(v1alpha1.TypeComponentReady))).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(blueprint.Status.Conditions, string(v1alpha1.TypeComponentProgressing))).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(blueprint.Status.Conditions, string(v1alpha1.TypeComponentDegraded))).To(BeTrue())
+	})
+
+	It("is degraded when an addon is unhealthy", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithObjects(
+			addonWithStatus(chartAddon.Name, v1alpha1.TypeComponentUnhealthy),
+			addonWithStatus(manifestAddon.Name, v1alpha1.TypeComponentProgressing),
+		).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+		blueprint := newBlueprint(chartAddon, manifestAddon)
+
+		summary, err := r.collectBlueprintStatus(ctx, blueprint, nil)
+		Expect(err).To(BeNil())
+
+		setBlueprintConditions(&blueprint.Status, summary, nil)
+		Expect(meta.IsStatusConditionFalse(blueprint.Status.Conditions, string(v1alpha1.TypeComponentReady))).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(blueprint.Status.Conditions, string(v1alpha1.TypeComponentProgressing))).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(blueprint.Status.Conditions, string(v1alpha1.TypeComponentDegraded))).To(BeTrue())
+	})
+
+	It("leaves dry run addons out of the readiness of the blueprint", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithObjects(
+			addonWithStatus(chartAddon.Name, v1alpha1.TypeComponentDryRun),
+			addonWithStatus(manifestAddon.Name, v1alpha1.TypeComponentProgressing),
+		).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		summary, err := r.collectBlueprintStatus(ctx, newBlueprint(chartAddon, manifestAddon), nil)
+		Expect(err).To(BeNil())
+		Expect(summary.addons[0].Type).To(Equal(v1alpha1.TypeComponentDryRun))
+		Expect(summary.progressing).To(Equal([]string{"addon " + manifestAddon.Name}))
+		Expect(summary.unhealthy).To(BeEmpty())
+	})
+
+	It("is degraded when the reconcile fails", func(ctx context.Context) {
+		status := &v1alpha1.BlueprintStatus{}
+		setBlueprintConditions(status, &blueprintStatusSummary{}, fmt.Errorf("failed"))
+
+		degraded := meta.FindStatusCondition(status.Conditions, string(v1alpha1.TypeComponentDegraded))
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal(reasonReconcileFailed))
+		Expect(meta.IsStatusConditionFalse(status.Conditions, string(v1alpha1.TypeComponentReady))).To(BeTrue())
+	})
+
+	It("keeps the transition time of statuses that have not changed", func(ctx context.Context) {
+		blueprint := newBlueprint(chartAddon, manifestAddon)
+		before := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		blueprint.Status.Addons = []v1alpha1.AddonStatusSummary{
+			{Name: chartAddon.Name, Status: v1alpha1.Status{Type: v1alpha1.TypeComponentProgressing, Reason: "Awaiting addon creation", LastTransitionTime: before}},
+			{Name: manifestAddon.Name, Status: v1alpha1.Status{Type: v1alpha1.TypeComponentAvailable, LastTransitionTime: before}},
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(blueprint).WithStatusSubresource(blueprint).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		summary, err := r.collectBlueprintStatus(ctx, blueprint, nil)
+		Expect(err).To(BeNil())
+		Expect(r.updateStatus(ctx, log.FromContext(ctx), blueprint, summary, nil)).To(Succeed())
+
+		Expect(fakeClient.Get(ctx, blueprintLookupKey, blueprint)).To(Succeed())
+		Expect(blueprint.Status.Addons[0].LastTransitionTime).To(Equal(before))
+		Expect(blueprint.Status.Addons[1].Type).To(Equal(v1alpha1.TypeComponentProgressing))
+		Expect(blueprint.Status.Addons[1].LastTransitionTime.After(before.Time)).To(BeTrue())
+	})
+
+	It("reports certificates that are being issued as progressing", func() {
+		cert := &v1.Certificate{Status: v1.CertificateStatus{Conditions: []v1.CertificateCondition{
+			{Type: v1.CertificateConditionReady, Status: cmmeta.ConditionFalse, Reason: "DoesNotExist"},
+			{Type: v1.CertificateConditionIssuing, Status: cmmeta.ConditionTrue},
+		}}}
+		Expect(certManagerResourceStatus(cert).Type).To(Equal(v1alpha1.TypeComponentProgressing))
+
+		cert.Status.Conditions[1].Status = cmmeta.ConditionFalse
+		Expect(certManagerResourceStatus(cert).Type).To(Equal(v1alpha1.TypeComponentUnhealthy))
+	})
+})
+
+var _ = Describe("Addon dependencies", func() {
+	var cni, csi, app v1alpha1.AddonSpec
+
+	BeforeEach(func() {
+		cni = v1alpha1.AddonSpec{Name: "cni", Namespace: "ns1", Kind: "manifest", Enabled: true, Manifest: &v1alpha1.ManifestInfo{URL: "https://example.com/cni.yaml"}}
+		csi = v1alpha1.AddonSpec{Name: "csi", Namespace: "ns1", Kind: "manifest", Enabled: true, Manifest: &v1alpha1.ManifestInfo{URL: "https://example.com/csi.yaml"}, DependsOn: []string{"cni"}}
+		app = v1alpha1.AddonSpec{Name: "app", Namespace: "ns1", Kind: "chart", Enabled: true, Chart: &v1alpha1.ChartInfo{Name: "app", DependsOn: []string{"csi"}}}
+	})
+
+	It("orders addons after their dependencies", func() {
+		sorted, err := sortAddonsByDependencies([]v1alpha1.AddonSpec{app, csi, cni})
+		Expect(err).To(BeNil())
+
+		var names []string
+		for _, a := range sorted {
+			names = append(names, a.Name)
+		}
+		Expect(names).To(Equal([]string{"cni", "csi", "app"}))
+	})
+
+	It("only creates an addon once its dependencies are available", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithStatusSubresource(&v1alpha1.Addon{}).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+		blueprint := newBlueprint(app, csi, cni)
+
+		waiting, err := r.reconcileAddons(ctx, log.FromContext(ctx), blueprint)
+		Expect(err).To(BeNil())
+		Expect(waiting).To(Equal(map[string][]string{"csi": {"cni"}, "app": {"csi"}}))
+
+		addons := &v1alpha1.AddonList{}
+		Expect(fakeClient.List(ctx, addons)).To(Succeed())
+		Expect(addons.Items).To(HaveLen(1))
+		Expect(addons.Items[0].Name).To(Equal("cni"))
+
+		// cni becomes available
+		addon := &addons.Items[0]
+		addon.Status.Type = v1alpha1.TypeComponentAvailable
+		addon.Status.ObservedGeneration = addon.Generation
+		Expect(fakeClient.Status().Update(ctx, addon)).To(Succeed())
+
+		waiting, err = r.reconcileAddons(ctx, log.FromContext(ctx), blueprint)
+		Expect(err).To(BeNil())
+		Expect(waiting).To(Equal(map[string][]string{"app": {"csi"}}))
+		Expect(fakeClient.List(ctx, addons)).To(Succeed())
+		Expect(addons.Items).To(HaveLen(2))
+
+		summary, err := r.collectBlueprintStatus(ctx, blueprint, waiting)
+		Expect(err).To(BeNil())
+		for _, s := range summary.addons {
+			if s.Name == "app" {
+				Expect(s.Type).To(Equal(v1alpha1.TypeComponentProgressing))
+				Expect(s.Message).To(ContainSubstring("csi"))
+			}
+		}
+	})
+
+	It("does not treat a dependency with a stale status as available", func(ctx context.Context) {
+		stale := &v1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: "cni", Namespace: consts.NamespaceBlueprintSystem, Generation: 2},
+			Status:     v1alpha1.AddonStatus{Status: v1alpha1.Status{Type: v1alpha1.TypeComponentAvailable}, ObservedGeneration: 1},
+		}
+		r := &BlueprintReconciler{Client: fake.NewClientBuilder().WithObjects(stale).Build()}
+
+		pending, err := r.pendingDependencies(ctx, csi, map[string]bool{})
+		Expect(err).To(BeNil())
+		Expect(pending).To(Equal([]string{"cni"}))
+	})
+
+	It("does not treat a dry run dependency as available", func(ctx context.Context) {
+		dryRun := &v1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: "cni", Namespace: consts.NamespaceBlueprintSystem},
+			Status:     v1alpha1.AddonStatus{Status: v1alpha1.Status{Type: v1alpha1.TypeComponentDryRun}},
+		}
+		r := &BlueprintReconciler{Client: fake.NewClientBuilder().WithObjects(dryRun).Build()}
+
+		pending, err := r.pendingDependencies(ctx, csi, map[string]bool{})
+		Expect(err).To(BeNil())
+		Expect(pending).To(Equal([]string{"cni"}))
+	})
+})
+
+var _ = Describe("Chart addons", func() {
+	createAddon := func(ctx context.Context, chart *v1alpha1.ChartInfo) *v1alpha1.Addon {
+		fakeClient := fake.NewClientBuilder().Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+		spec := v1alpha1.AddonSpec{Name: "app", Namespace: "ns1", Kind: "chart", Enabled: true, Chart: chart}
+
+		_, err := r.reconcileAddons(ctx, log.FromContext(ctx), newBlueprint(spec))
+		Expect(err).To(BeNil())
+
+		addon := &v1alpha1.Addon{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: consts.NamespaceBlueprintSystem}, addon)).To(Succeed())
+		return addon
+	}
+
+	It("passes the repository credentials from the blueprint", func(ctx context.Context) {
+		addon := createAddon(ctx, &v1alpha1.ChartInfo{
+			Name:            "app",
+			Repo:            "https://charts.example.com",
+			Version:         "1.0.0",
+			SecretRef:       &v1alpha1.SecretReference{Name: "repo-credentials"},
+			CertSecretRef:   &v1alpha1.SecretReference{Name: "repo-ca"},
+			PassCredentials: true,
+			Insecure:        true,
+		})
+
+		Expect(addon.Spec.Chart.SecretRef).To(Equal(&v1alpha1.SecretReference{Name: "repo-credentials"}))
+		Expect(addon.Spec.Chart.CertSecretRef).To(Equal(&v1alpha1.SecretReference{Name: "repo-ca"}))
+		Expect(addon.Spec.Chart.PassCredentials).To(BeTrue())
+		Expect(addon.Spec.Chart.Insecure).To(BeTrue())
+	})
+
+	It("passes the values references from the blueprint", func(ctx context.Context) {
+		valuesFrom := []v1alpha1.ValuesReference{
+			{Kind: "ConfigMap", Name: "app-values"},
+			{Kind: "Secret", Name: "app-secrets", ValuesKey: "password", TargetPath: "auth.password", Optional: true},
+		}
+		addon := createAddon(ctx, &v1alpha1.ChartInfo{Name: "app", Repo: "https://charts.example.com", Version: "1.0.0", ValuesFrom: valuesFrom})
+
+		Expect(addon.Spec.Chart.ValuesFrom).To(Equal(valuesFrom))
+	})
+
+	It("passes the kustomize patches and image overrides from the blueprint", func(ctx context.Context) {
+		kustomize := &v1alpha1.Values{
+			Images: []v1alpha1.Image{{Name: "nginx", NewName: "registry.example.com/nginx"}},
+		}
+		addon := createAddon(ctx, &v1alpha1.ChartInfo{Name: "app", Repo: "https://charts.example.com", Version: "1.0.0", Kustomize: kustomize})
+
+		Expect(addon.Spec.Chart.Kustomize).To(Equal(kustomize))
+	})
+
+	It("passes the helm options from the blueprint", func(ctx context.Context) {
+		retries := int32(5)
+		options := &v1alpha1.HelmOptions{Wait: true, Timeout: "10m", InstallRetries: &retries, RemediationStrategy: "uninstall", CRDs: "CreateReplace"}
+		addon := createAddon(ctx, &v1alpha1.ChartInfo{Name: "app", Repo: "https://charts.example.com", Version: "1.0.0", HelmOptions: options})
+
+		Expect(addon.Spec.Chart.HelmOptions).To(Equal(options))
+	})
+})
+
+var _ = Describe("Blueprint deletion", func() {
+	var cni, csi, app v1alpha1.AddonSpec
+
+	BeforeEach(func() {
+		cni = v1alpha1.AddonSpec{Name: "cni", Namespace: "ns1", Kind: "manifest", Enabled: true, Manifest: &v1alpha1.ManifestInfo{URL: "https://example.com/cni.yaml"}}
+		csi = v1alpha1.AddonSpec{Name: "csi", Namespace: "ns1", Kind: "manifest", Enabled: true, Manifest: &v1alpha1.ManifestInfo{URL: "https://example.com/csi.yaml"}, DependsOn: []string{"cni"}}
+		app = v1alpha1.AddonSpec{Name: "app", Namespace: "ns1", Kind: "chart", Enabled: true, Chart: &v1alpha1.ChartInfo{Name: "app", DependsOn: []string{"csi"}}}
+	})
+
+	deletedBlueprint := func(policy v1alpha1.DeletionPolicy) *v1alpha1.Blueprint {
+		blueprint := newBlueprint(cni, csi, app)
+		blueprint.Spec.DeletionPolicy = policy
+		blueprint.Finalizers = []string{blueprintFinalizer}
+		blueprint.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		return blueprint
+	}
+
+	foreignAddon := func() *v1alpha1.Addon {
+		return &v1alpha1.Addon{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: consts.NamespaceBlueprintSystem}}
+	}
+
+	owned := func(obj client.Object) client.Object {
+		setOwner(obj, newBlueprint())
+		return obj
+	}
+
+	addonNames := func(ctx context.Context, c client.Client) []string {
+		addons := &v1alpha1.AddonList{}
+		Expect(c.List(ctx, addons)).To(Succeed())
+		var names []string
+		for _, a := range addons.Items {
+			names = append(names, a.Name)
+		}
+		return names
+	}
+
+	It("uninstalls addons in reverse dependency order before deleting resources", func(ctx context.Context) {
+		issuer := owned(issuerObject(v1alpha1.Issuer{Name: "issuer1", Namespace: "ns1"}))
+		blueprint := deletedBlueprint(v1alpha1.DeletionPolicyDelete)
+		fakeClient := fake.NewClientBuilder().
+			WithObjects(blueprint, owned(addonResource(&cni)), owned(addonResource(&csi)), owned(addonResource(&app)), foreignAddon(), issuer).
+			WithStatusSubresource(&v1alpha1.Blueprint{}).
+			Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		for _, expected := range [][]string{{"cni", "csi", "foreign"}, {"cni", "foreign"}, {"foreign"}} {
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: blueprintLookupKey})
+			Expect(err).To(BeNil())
+			Expect(result.RequeueAfter).To(Equal(DefaultRequeueDuration))
+			Expect(addonNames(ctx, fakeClient)).To(Equal(expected))
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(issuer), &v1.Issuer{})).To(Succeed())
+
+			Expect(fakeClient.Get(ctx, blueprintLookupKey, blueprint)).To(Succeed())
+			ready := meta.FindStatusCondition(blueprint.Status.Conditions, string(v1alpha1.TypeComponentReady))
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal(reasonDeleting))
+		}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: blueprintLookupKey})
+		Expect(err).To(BeNil())
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(issuer), &v1.Issuer{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, blueprintLookupKey, &v1alpha1.Blueprint{}))).To(BeTrue())
+	})
+
+	It("leaves orphaned addons and resources in place without the managed-by label", func(ctx context.Context) {
+		issuer := owned(issuerObject(v1alpha1.Issuer{Name: "issuer1", Namespace: "ns1"}))
+		fakeClient := fake.NewClientBuilder().
+			WithObjects(deletedBlueprint(v1alpha1.DeletionPolicyOrphan), owned(addonResource(&cni)), owned(addonResource(&app)), issuer).
+			WithStatusSubresource(&v1alpha1.Blueprint{}).
+			Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: blueprintLookupKey})
+		Expect(err).To(BeNil())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, blueprintLookupKey, &v1alpha1.Blueprint{}))).To(BeTrue())
+
+		addons := &v1alpha1.AddonList{}
+		Expect(fakeClient.List(ctx, addons)).To(Succeed())
+		Expect(addons.Items).To(HaveLen(2))
+		for _, a := range addons.Items {
+			Expect(a.Labels).NotTo(HaveKey(consts.ManagedByLabel))
+			Expect(a.Labels).NotTo(HaveKey(consts.BlueprintNameLabel))
+		}
+
+		orphaned := &v1.Issuer{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(issuer), orphaned)).To(Succeed())
+		Expect(orphaned.Labels).NotTo(HaveKey(consts.ManagedByLabel))
+		Expect(orphaned.Labels).NotTo(HaveKey(consts.BlueprintNameLabel))
+	})
+
+	It("adds the finalizer to the blueprint", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithObjects(newBlueprint()).WithStatusSubresource(&v1alpha1.Blueprint{}).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: blueprintLookupKey})
+		Expect(err).To(BeNil())
+
+		blueprint := &v1alpha1.Blueprint{}
+		Expect(fakeClient.Get(ctx, blueprintLookupKey, blueprint)).To(Succeed())
+		Expect(blueprint.Finalizers).To(ContainElement(blueprintFinalizer))
+	})
+})
+
+var _ = Describe("Multiple blueprints", func() {
+	var platform, apps *v1alpha1.Blueprint
+	var cni, wordpress v1alpha1.AddonSpec
+
+	BeforeEach(func() {
+		cni = v1alpha1.AddonSpec{Name: "cni", Namespace: "ns1", Kind: "manifest", Enabled: true, Manifest: &v1alpha1.ManifestInfo{URL: "https://example.com/cni.yaml"}}
+		wordpress = v1alpha1.AddonSpec{Name: "wordpress", Namespace: "ns2", Kind: "chart", Enabled: true, Chart: &v1alpha1.ChartInfo{Name: "wordpress"}}
+
+		platform = newBlueprint(cni)
+		platform.Name = "platform"
+		platform.Spec.Resources.CertManagement.Issuers = []v1alpha1.Issuer{{Name: "platform-issuer", Namespace: "ns1"}}
+		apps = newBlueprint(wordpress)
+		apps.Name = "apps"
+		apps.Spec.Resources.CertManagement.Issuers = []v1alpha1.Issuer{{Name: "apps-issuer", Namespace: "ns2"}}
+	})
+
+	It("does not delete the addons and resources of other blueprints", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithStatusSubresource(&v1alpha1.Addon{}).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		for i := 0; i < 2; i++ {
+			for _, b := range []*v1alpha1.Blueprint{platform, apps} {
+				_, err := r.reconcileComponents(ctx, log.FromContext(ctx), b)
+				Expect(err).To(BeNil())
+			}
+		}
+
+		addons := &v1alpha1.AddonList{}
+		Expect(fakeClient.List(ctx, addons)).To(Succeed())
+		Expect(addons.Items).To(HaveLen(2))
+		for _, a := range addons.Items {
+			owner := platform
+			if a.Name == wordpress.Name {
+				owner = apps
+			}
+			Expect(isOwnedBy(&a, owner)).To(BeTrue(), "addon %s is not owned by blueprint %s", a.Name, owner.Name)
+		}
+
+		issuers := &v1.IssuerList{}
+		Expect(fakeClient.List(ctx, issuers)).To(Succeed())
+		Expect(issuers.Items).To(HaveLen(2))
+
+		By("Removing the addon from one blueprint")
+		apps.Spec.Components.Addons = nil
+		_, err := r.reconcileComponents(ctx, log.FromContext(ctx), apps)
+		Expect(err).To(BeNil())
+		Expect(fakeClient.List(ctx, addons)).To(Succeed())
+		Expect(addons.Items).To(HaveLen(1))
+		Expect(addons.Items[0].Name).To(Equal(cni.Name))
+	})
+
+	It("does not take over the addons of other blueprints", func(ctx context.Context) {
+		existing := addonResource(&cni)
+		setOwner(existing, platform)
+		fakeClient := fake.NewClientBuilder().WithObjects(existing).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		apps.Spec.Components.Addons = []v1alpha1.AddonSpec{cni}
+		_, err := r.reconcileAddons(ctx, log.FromContext(ctx), apps)
+		Expect(err).To(MatchError(ContainSubstring("already owned by blueprint blueprint-system/platform")))
+
+		addon := &v1alpha1.Addon{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(existing), addon)).To(Succeed())
+		Expect(isOwnedBy(addon, platform)).To(BeTrue())
+	})
+
+	It("adopts addons without an owner", func(ctx context.Context) {
+		existing := addonResource(&cni)
+		fakeClient := fake.NewClientBuilder().WithObjects(existing).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		_, err := r.reconcileAddons(ctx, log.FromContext(ctx), platform)
+		Expect(err).To(BeNil())
+
+		addon := &v1alpha1.Addon{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(existing), addon)).To(Succeed())
+		Expect(isOwnedBy(addon, platform)).To(BeTrue())
+	})
+
+	It("prunes addons and resources created before blueprints owned them", func(ctx context.Context) {
+		legacyAddon := addonResource(&wordpress)
+		legacyIssuer := issuerObject(v1alpha1.Issuer{Name: "legacy-issuer", Namespace: "ns1"})
+		fakeClient := fake.NewClientBuilder().WithObjects(platform, legacyAddon, legacyIssuer).WithStatusSubresource(&v1alpha1.Addon{}).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		_, err := r.reconcileComponents(ctx, log.FromContext(ctx), platform)
+		Expect(err).To(BeNil())
+
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacyAddon), &v1alpha1.Addon{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacyIssuer), &v1.Issuer{}))).To(BeTrue())
+	})
+
+	It("leaves legacy addons declared by another blueprint to that blueprint", func(ctx context.Context) {
+		legacyAddon := addonResource(&wordpress)
+		fakeClient := fake.NewClientBuilder().WithObjects(platform, apps, legacyAddon).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		_, err := r.reconcileAddons(ctx, log.FromContext(ctx), platform)
+		Expect(err).To(BeNil())
+
+		addon := &v1alpha1.Addon{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacyAddon), addon)).To(Succeed())
+		_, owned := ownerOf(addon)
+		Expect(owned).To(BeFalse())
+
+		_, err = r.reconcileAddons(ctx, log.FromContext(ctx), apps)
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacyAddon), addon)).To(Succeed())
+		Expect(isOwnedBy(addon, apps)).To(BeTrue())
+	})
+
+	It("only reconciles the blueprint owning an addon or manifest", func(ctx context.Context) {
+		addon := addonResource(&cni)
+		setOwner(addon, platform)
+		orphan := addonResource(&wordpress)
+		fakeClient := fake.NewClientBuilder().WithObjects(platform, apps, addon, orphan).Build()
+		r := &BlueprintReconciler{Client: fakeClient}
+
+		platformRequest := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(platform)}
+		Expect(r.findBlueprintsForObject(ctx, addon)).To(Equal([]reconcile.Request{platformRequest}))
+
+		manifest := &v1alpha1.Manifest{ObjectMeta: metav1.ObjectMeta{Name: cni.Name, Namespace: consts.NamespaceBlueprintSystem}}
+		Expect(r.findBlueprintsForObject(ctx, manifest)).To(Equal([]reconcile.Request{platformRequest}))
+
+		Expect(r.findBlueprintsForObject(ctx, orphan)).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+const (
+	blueprintFinalizer = "blueprint.mirantis.com/blueprint-finalizer"
+
+	// reasonDeleting is used while the addons and resources of a deleted blueprint are being removed
+	reasonDeleting = "Deleting"
+)
+
+// finalizeBlueprint applies the deletion policy of a blueprint being deleted
+// It returns the addons and resources that are still being removed. The finalizer of the blueprint can only be
+// removed once there are none left.
+func (r *BlueprintReconciler) finalizeBlueprint(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint) ([]string, error) {
+	installed, err := r.getInstalledAddons(ctx, logger, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if instance.Spec.DeletionPolicy == v1alpha1.DeletionPolicyOrphan {
+		logger.Info("Orphaning blueprint components", "Name", instance.Name)
+		return nil, r.orphanComponents(ctx, logger, instance, installed)
+	}
+
+	remaining, err := r.uninstallAddons(ctx, logger, installed)
+	if err != nil || len(remaining) > 0 {
+		// resources may be used by the addons, so they are only deleted once the addons are gone
+		return remaining, err
+	}
+
+	for _, lister := range []ItemsLister{listCertificates, listIssuers, listClusterIssuers} {
+		objects, err := listInstalledObjects(ctx, logger, r.Client, instance, lister)
+		if err != nil {
+			return nil, err
+		}
+		if err = deleteObjects(ctx, logger, r.Client, objects); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// uninstallAddons deletes the installed addons in reverse dependency order
+// An addon is only deleted once no other installed addon depends on it, and the addons still installed are
+// returned. If the dependencies form a cycle, the addons of the cycle are deleted together.
+// A deleted addon is kept by its finalizer until its release or manifest is gone, so its dependencies are only
+// deleted once it is actually uninstalled.
+func (r *BlueprintReconciler) uninstallAddons(ctx context.Context, logger logr.Logger, installed map[string]v1alpha1.Addon) ([]string, error) {
+	dependents := map[string][]string{}
+	for name, addon := range installed {
+		for _, dep := range addon.Spec.AllDependencies() {
+			if _, ok := installed[dep]; ok {
+				dependents[dep] = append(dependents[dep], name)
+			}
+		}
+	}
+
+	var remaining, deletable, blocked []string
+	terminating := false
+	for name, addon := range installed {
+		remaining = append(remaining, name)
+		if !addon.DeletionTimestamp.IsZero() {
+			terminating = true
+			continue
+		}
+		if len(dependents[name]) > 0 {
+			logger.Info("Addon is waiting for its dependents to be uninstalled", "Name", name, "Dependents", dependents[name])
+			blocked = append(blocked, name)
+			continue
+		}
+		deletable = append(deletable, name)
+	}
+
+	if len(deletable) == 0 && !terminating {
+		// nothing can make progress, which only happens with a dependency cycle
+		deletable = blocked
+	}
+
+	toDelete := map[string]v1alpha1.Addon{}
+	for _, name := range deletable {
+		toDelete[name] = installed[name]
+	}
+	if err := r.deleteAddons(ctx, logger, toDelete); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+// orphanComponents removes the managed-by and owner labels from the installed addons and resources, so that they
+// are left in place and are no longer considered as managed by a blueprint
+func (r *BlueprintReconciler) orphanComponents(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint, installed map[string]v1alpha1.Addon) error {
+	for name := range installed {
+		addon := installed[name]
+		if err := orphanObject(ctx, logger, r.Client, &addon); err != nil {
+			return err
+		}
+	}
+
+	for _, lister := range []ItemsLister{listCertificates, listIssuers, listClusterIssuers} {
+		objects, err := listInstalledObjects(ctx, logger, r.Client, instance, lister)
+		if err != nil {
+			return err
+		}
+		for _, o := range objects {
+			if err = orphanObject(ctx, logger, r.Client, o); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// orphanObject removes the managed-by and owner labels from the object
+func orphanObject(ctx context.Context, logger logr.Logger, apiClient client.Client, obj client.Object) error {
+	logger.Info("Orphaning object", "Name", obj.GetName(), "Namespace", obj.GetNamespace())
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	removeOwner(obj)
+	if err := apiClient.Patch(ctx, obj, patch); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to orphan %s: %w", generateName(obj), err)
+	}
+	return nil
+}
+
+// updateDeletionStatus reports the progress of the deletion of the blueprint in its conditions
+func (r *BlueprintReconciler) updateDeletionStatus(ctx context.Context, instance *v1alpha1.Blueprint, remaining []string, deleteErr error) error {
+	patch := client.MergeFrom(instance.DeepCopy())
+	setDeletionConditions(&instance.Status, remaining, deleteErr)
+	return r.Status().Patch(ctx, instance, patch)
+}
+
+// setDeletionConditions sets the Ready, Progressing and Degraded conditions of a blueprint being deleted
+func setDeletionConditions(status *v1alpha1.BlueprintStatus, remaining []string, deleteErr error) {
+	msg := "Removing addons and resources"
+	if len(remaining) > 0 {
+		msg = fmt.Sprintf("Uninstalling addons: %s", strings.Join(remaining, ", "))
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonDeleting,
+		Message:            msg,
+		ObservedGeneration: status.ObservedGeneration,
+	})
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentProgressing),
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonDeleting,
+		Message:            msg,
+		ObservedGeneration: status.ObservedGeneration,
+	})
+
+	degraded := metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentDegraded),
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonNoFailures,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	if deleteErr != nil {
+		degraded.Status, degraded.Reason, degraded.Message = metav1.ConditionTrue, reasonReconcileFailed, deleteErr.Error()
+	}
+	meta.SetStatusCondition(&status.Conditions, degraded)
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/dag"
+)
+
+// sortAddonsByDependencies returns the enabled addons ordered so that every addon comes after its dependencies
+// Dependencies on addons that are not enabled are not part of the ordering; such addons never become available.
+func sortAddonsByDependencies(addons []v1alpha1.AddonSpec) ([]v1alpha1.AddonSpec, error) {
+	specs := map[string]v1alpha1.AddonSpec{}
+	for _, spec := range addons {
+		if spec.Enabled {
+			specs[spec.Name] = spec
+		}
+	}
+
+	graph := dag.New()
+	for _, spec := range addons {
+		if !spec.Enabled {
+			continue
+		}
+		graph.AddNode(spec.Name)
+		for _, dep := range spec.AllDependencies() {
+			if _, ok := specs[dep]; ok {
+				graph.AddNode(spec.Name, dep)
+			}
+		}
+	}
+
+	order, err := graph.TopologicalSort()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order addons by dependencies: %w", err)
+	}
+
+	sorted := make([]v1alpha1.AddonSpec, 0, len(order))
+	for _, name := range order {
+		sorted = append(sorted, specs[name])
+	}
+	return sorted, nil
+}
+
+// pendingDependencies returns the dependencies of the addon that are not yet available
+// Addons in notReady are considered unavailable regardless of their status, because they are waiting for
+// their own dependencies or were changed during this reconcile.
+func (r *BlueprintReconciler) pendingDependencies(ctx context.Context, spec v1alpha1.AddonSpec, notReady map[string]bool) ([]string, error) {
+	var pending []string
+	for _, dep := range spec.AllDependencies() {
+		if notReady[dep] {
+			pending = append(pending, dep)
+			continue
+		}
+
+		addon := &v1alpha1.Addon{}
+		if err := r.Get(ctx, client.ObjectKey{Name: dep, Namespace: consts.NamespaceBlueprintSystem}, addon); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get addon %s: %w", dep, err)
+			}
+			pending = append(pending, dep)
+			continue
+		}
+
+		if addon.Status.Type != v1alpha1.TypeComponentAvailable || addon.Status.ObservedGeneration != addon.Generation {
+			pending = append(pending, dep)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+// Addons and resources are owned by the blueprint that declares them through labels rather than owner references,
+// because a blueprint can't own the Addons in the blueprint-system namespace nor cluster scoped resources. The
+// blueprint finalizer takes care of removing them as per the deletion policy of the blueprint.
+
+// ownerLabels returns the labels identifying the addons and resources owned by the blueprint
+func ownerLabels(owner *v1alpha1.Blueprint) map[string]string {
+	return map[string]string{
+		consts.ManagedByLabel:          consts.ManagedByValue,
+		consts.BlueprintNameLabel:      owner.Name,
+		consts.BlueprintNamespaceLabel: owner.Namespace,
+	}
+}
+
+// setOwner labels the object as owned by the blueprint
+func setOwner(obj client.Object, owner *v1alpha1.Blueprint) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range ownerLabels(owner) {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+}
+
+// removeOwner removes the labels identifying the object as managed by a blueprint
+func removeOwner(obj client.Object) {
+	labels := obj.GetLabels()
+	delete(labels, consts.ManagedByLabel)
+	delete(labels, consts.BlueprintNameLabel)
+	delete(labels, consts.BlueprintNamespaceLabel)
+	obj.SetLabels(labels)
+}
+
+// isOwnedBy returns true if the object is labelled as owned by the blueprint
+func isOwnedBy(obj client.Object, owner *v1alpha1.Blueprint) bool {
+	key, ok := ownerOf(obj)
+	return ok && key == client.ObjectKeyFromObject(owner)
+}
+
+// ownerOf returns the blueprint that owns the object, if any
+func ownerOf(obj client.Object) (types.NamespacedName, bool) {
+	labels := obj.GetLabels()
+	name := labels[consts.BlueprintNameLabel]
+	if name == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Name: name, Namespace: labels[consts.BlueprintNamespaceLabel]}, true
+}
+
+// checkOwner returns an error if the existing object is owned by another blueprint than the desired object
+// Objects that are not owned by any blueprint, e.g. because they were created by a previous version of the
+// operator, are adopted.
+func checkOwner(existing, desired client.Object) error {
+	existingOwner, ok := ownerOf(existing)
+	if !ok {
+		return nil
+	}
+	if desiredOwner, _ := ownerOf(desired); desiredOwner != existingOwner {
+		return fmt.Errorf("%s is already owned by blueprint %s", generateName(existing), existingOwner)
+	}
+	return nil
+}
+
+// adoptLegacyObjects labels the objects that are managed by the operator but not owned by any blueprint as owned
+// by the blueprint, as checkOwner does when they are created or updated, and returns the adopted objects
+// Such objects were created by a previous version of the operator. Adopting them when listing the installed
+// objects lets the blueprint prune them once they are no longer desired. The manifest of an addon follows its
+// addon. Objects declared by another blueprint are left for that blueprint to adopt.
+func adoptLegacyObjects(ctx context.Context, logger logr.Logger, apiClient client.Client, owner *v1alpha1.Blueprint, objects []client.Object) ([]client.Object, error) {
+	var legacy []client.Object
+	for _, obj := range objects {
+		if _, ok := ownerOf(obj); !ok && obj.GetLabels()[consts.ManagedByLabel] == consts.ManagedByValue {
+			legacy = append(legacy, obj)
+		}
+	}
+	if len(legacy) == 0 {
+		return nil, nil
+	}
+
+	blueprints := &v1alpha1.BlueprintList{}
+	if err := apiClient.List(ctx, blueprints); err != nil {
+		return nil, err
+	}
+	declared := map[string]bool{}
+	for i := range blueprints.Items {
+		if client.ObjectKeyFromObject(&blueprints.Items[i]) == client.ObjectKeyFromObject(owner) {
+			continue
+		}
+		for _, obj := range declaredObjects(&blueprints.Items[i]) {
+			declared[objectKey(obj)] = true
+		}
+	}
+
+	var adopted []client.Object
+	for _, obj := range legacy {
+		if declared[objectKey(obj)] {
+			logger.Info("Leaving object declared by another blueprint", "Name", obj.GetName(), "Namespace", obj.GetNamespace())
+			continue
+		}
+
+		logger.Info("Adopting object", "Name", obj.GetName(), "Namespace", obj.GetNamespace())
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		setOwner(obj, owner)
+		if err := apiClient.Patch(ctx, obj, patch); err != nil {
+			return nil, fmt.Errorf("failed to adopt %s: %w", generateName(obj), err)
+		}
+		adopted = append(adopted, obj)
+	}
+	return adopted, nil
+}
+
+// declaredObjects returns the addons and resources declared in the spec of the blueprint
+func declaredObjects(blueprint *v1alpha1.Blueprint) []client.Object {
+	var objects []client.Object
+	for i := range blueprint.Spec.Components.Addons {
+		objects = append(objects, addonResource(&blueprint.Spec.Components.Addons[i]))
+	}
+
+	certManagement := blueprint.Spec.Resources.CertManagement
+	objects = append(objects, convertToObjects(certManagement.Issuers, issuerObject)...)
+	objects = append(objects, convertToObjects(certManagement.ClusterIssuers, clusterIssuerObject)...)
+	return append(objects, convertToObjects(certManagement.Certificates, certificateObject)...)
+}
+
+// objectKey identifies an object by its type, namespace and name
+func objectKey(obj client.Object) string {
+	return fmt.Sprintf("%T %s", obj, generateName(obj))
+}
+
+// getOwningAddon returns the addon owning the manifest, which is named after the addon
+func (r *BlueprintReconciler) getOwningAddon(ctx context.Context, manifest client.Object) (*v1alpha1.Addon, error) {
+	addon := &v1alpha1.Addon{}
+	key := client.ObjectKey{Name: manifest.GetName(), Namespace: consts.NamespaceBlueprintSystem}
+	if err := r.Get(ctx, key, addon); err != nil {
+		return nil, err
+	}
+	return addon, nil
+}
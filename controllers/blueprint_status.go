@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+const (
+	// reasonAllComponentsAvailable is used when every addon and resource of the blueprint is available
+	reasonAllComponentsAvailable = "AllComponentsAvailable"
+	// reasonComponentsProgressing is used when one or more addons or resources are still being installed
+	reasonComponentsProgressing = "ComponentsProgressing"
+	// reasonComponentsUnhealthy is used when one or more addons or resources are unhealthy
+	reasonComponentsUnhealthy = "ComponentsUnhealthy"
+	// reasonReconcileFailed is used when the blueprint controller failed to reconcile the blueprint
+	reasonReconcileFailed = "ReconcileFailed"
+	// reasonNoFailures is used for the Degraded condition when nothing is failing
+	reasonNoFailures = "NoFailures"
+)
+
+// blueprintStatusSummary is an intermediate aggregation of the status of all addons and resources of a blueprint
+type blueprintStatusSummary struct {
+	addons    []v1alpha1.AddonStatusSummary
+	resources []v1alpha1.ResourceStatusSummary
+
+	progressing []string
+	unhealthy   []string
+}
+
+// ready returns true if every addon and resource is available
+func (s *blueprintStatusSummary) ready() bool {
+	return len(s.progressing) == 0 && len(s.unhealthy) == 0
+}
+
+// resourcesReady returns true if every cert-manager resource is available
+func (s *blueprintStatusSummary) resourcesReady() bool {
+	for _, res := range s.resources {
+		if res.Type != v1alpha1.TypeComponentAvailable {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *blueprintStatusSummary) record(name string, statusType v1alpha1.StatusType) {
+	switch statusType {
+	case v1alpha1.TypeComponentAvailable, v1alpha1.TypeComponentReady:
+	case v1alpha1.TypeComponentDryRun:
+		// dry run addons are not installed, so they don't take part in the readiness of the blueprint
+	case v1alpha1.TypeComponentUnhealthy, v1alpha1.TypeComponentDegraded:
+		s.unhealthy = append(s.unhealthy, name)
+	default:
+		s.progressing = append(s.progressing, name)
+	}
+}
+
+// collectBlueprintStatus gathers the status of every enabled addon and every cert-manager resource of the blueprint
+// Addons in waiting are reported as progressing until their dependencies are available.
+func (r *BlueprintReconciler) collectBlueprintStatus(ctx context.Context, instance *v1alpha1.Blueprint, waiting map[string][]string) (*blueprintStatusSummary, error) {
+	summary := &blueprintStatusSummary{}
+
+	for _, spec := range instance.Spec.Components.Addons {
+		if !spec.Enabled {
+			continue
+		}
+
+		addonStatus, err := r.addonStatusSummary(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		if pending, ok := waiting[spec.Name]; ok {
+			addonStatus.Status = v1alpha1.Status{
+				Type:               v1alpha1.TypeComponentProgressing,
+				Reason:             "Waiting for dependencies",
+				Message:            fmt.Sprintf("Waiting for addons to be available: %s", strings.Join(pending, ", ")),
+				LastTransitionTime: metav1.Now(),
+			}
+		}
+		summary.addons = append(summary.addons, addonStatus)
+		summary.record(fmt.Sprintf("addon %s", spec.Name), addonStatus.Type)
+	}
+
+	certManagement := instance.Spec.Resources.CertManagement
+	var objects []client.Object
+	objects = append(objects, convertToObjects(certManagement.Issuers, issuerObject)...)
+	objects = append(objects, convertToObjects(certManagement.ClusterIssuers, clusterIssuerObject)...)
+	objects = append(objects, convertToObjects(certManagement.Certificates, certificateObject)...)
+
+	for _, o := range objects {
+		resourceStatus, err := r.resourceStatusSummary(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		summary.resources = append(summary.resources, resourceStatus)
+		summary.record(fmt.Sprintf("%s %s", resourceStatus.Kind, generateName(o)), resourceStatus.Type)
+	}
+
+	return summary, nil
+}
+
+// addonStatusSummary returns the status of the Addon object created for the given addon spec
+func (r *BlueprintReconciler) addonStatusSummary(ctx context.Context, spec v1alpha1.AddonSpec) (v1alpha1.AddonStatusSummary, error) {
+	summary := v1alpha1.AddonStatusSummary{
+		Name:      spec.Name,
+		Namespace: spec.Namespace,
+		Kind:      spec.Kind,
+	}
+
+	addon := &v1alpha1.Addon{}
+	if err := r.Get(ctx, client.ObjectKey{Name: spec.Name, Namespace: consts.NamespaceBlueprintSystem}, addon); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return summary, fmt.Errorf("failed to get addon %s: %w", spec.Name, err)
+		}
+		summary.Status = v1alpha1.Status{
+			Type:               v1alpha1.TypeComponentProgressing,
+			Reason:             "Awaiting addon creation",
+			LastTransitionTime: metav1.Now(),
+		}
+		return summary, nil
+	}
+
+	if addon.Status.Type == "" {
+		summary.Status = v1alpha1.Status{
+			Type:               v1alpha1.TypeComponentProgressing,
+			Reason:             "Awaiting status from addon object",
+			LastTransitionTime: addon.CreationTimestamp,
+		}
+		return summary, nil
+	}
+
+	summary.Status = addon.Status.Status
+	return summary, nil
+}
+
+// resourceStatusSummary returns the status of a cert-manager resource based on its Ready condition
+func (r *BlueprintReconciler) resourceStatusSummary(ctx context.Context, desired client.Object) (v1alpha1.ResourceStatusSummary, error) {
+	summary := v1alpha1.ResourceStatusSummary{
+		Kind:      desired.GetObjectKind().GroupVersionKind().Kind,
+		Name:      desired.GetName(),
+		Namespace: desired.GetNamespace(),
+	}
+
+	existing := desired.DeepCopyObject().(client.Object)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return summary, fmt.Errorf("failed to get %s %s: %w", summary.Kind, generateName(desired), err)
+		}
+		summary.Status = v1alpha1.Status{
+			Type:               v1alpha1.TypeComponentProgressing,
+			Reason:             fmt.Sprintf("Awaiting %s creation", summary.Kind),
+			LastTransitionTime: metav1.Now(),
+		}
+		return summary, nil
+	}
+
+	summary.Status = certManagerResourceStatus(existing)
+	return summary, nil
+}
+
+// certManagerResourceStatus converts the Ready condition of an Issuer, ClusterIssuer or Certificate into a Status
+func certManagerResourceStatus(obj client.Object) v1alpha1.Status {
+	var (
+		ready, issuing *cmmeta.ConditionStatus
+		reason         string
+		message        string
+		transition     *metav1.Time
+	)
+
+	switch o := obj.(type) {
+	case *certmanager.Issuer, *certmanager.ClusterIssuer:
+		issuer := o.(certmanager.GenericIssuer)
+		for _, c := range issuer.GetStatus().Conditions {
+			if c.Type == certmanager.IssuerConditionReady {
+				ready, reason, message, transition = &c.Status, c.Reason, c.Message, c.LastTransitionTime
+			}
+		}
+	case *certmanager.Certificate:
+		for _, c := range o.Status.Conditions {
+			switch c.Type {
+			case certmanager.CertificateConditionReady:
+				ready, reason, message, transition = &c.Status, c.Reason, c.Message, c.LastTransitionTime
+			case certmanager.CertificateConditionIssuing:
+				issuing = &c.Status
+			}
+		}
+	}
+
+	status := v1alpha1.Status{Reason: reason, Message: message, LastTransitionTime: obj.GetCreationTimestamp()}
+	if transition != nil {
+		status.LastTransitionTime = *transition
+	}
+
+	switch {
+	case ready == nil:
+		status.Type = v1alpha1.TypeComponentProgressing
+		status.Reason = "Awaiting Ready condition"
+	case *ready == cmmeta.ConditionTrue:
+		status.Type = v1alpha1.TypeComponentAvailable
+	case issuing != nil && *issuing == cmmeta.ConditionTrue:
+		// certificate is not ready because it is still being issued
+		status.Type = v1alpha1.TypeComponentProgressing
+	case *ready == cmmeta.ConditionFalse:
+		status.Type = v1alpha1.TypeComponentUnhealthy
+	default:
+		status.Type = v1alpha1.TypeComponentProgressing
+	}
+
+	return status
+}
+
+// updateStatus writes the aggregated status of the addons and resources to the blueprint status
+// If reconcileErr is not nil, the blueprint is marked as Degraded.
+func (r *BlueprintReconciler) updateStatus(ctx context.Context, logger logr.Logger, instance *v1alpha1.Blueprint, summary *blueprintStatusSummary, reconcileErr error) error {
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	keepTransitionTimes(&instance.Status, summary)
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.Addons = summary.addons
+	instance.Status.Resources = summary.resources
+	setBlueprintConditions(&instance.Status, summary, reconcileErr)
+
+	logger.Info("Update status for blueprint", "Name", instance.Name, "Ready", summary.ready() && reconcileErr == nil)
+	return r.Status().Patch(ctx, instance, patch)
+}
+
+// keepTransitionTimes keeps the transition time recorded in the blueprint status for the addons and resources whose
+// status type and reason haven't changed, as meta.SetStatusCondition does for conditions
+// Statuses synthesized by the blueprint controller are timestamped on every reconcile, and would otherwise change
+// the blueprint status each time.
+func keepTransitionTimes(status *v1alpha1.BlueprintStatus, summary *blueprintStatusSummary) {
+	addons := map[string]v1alpha1.Status{}
+	for _, a := range status.Addons {
+		addons[a.Name] = a.Status
+	}
+	for i := range summary.addons {
+		keepTransitionTime(addons[summary.addons[i].Name], &summary.addons[i].Status)
+	}
+
+	resources := map[string]v1alpha1.Status{}
+	for _, res := range status.Resources {
+		resources[fmt.Sprintf("%s %s/%s", res.Kind, res.Namespace, res.Name)] = res.Status
+	}
+	for i := range summary.resources {
+		res := &summary.resources[i]
+		keepTransitionTime(resources[fmt.Sprintf("%s %s/%s", res.Kind, res.Namespace, res.Name)], &res.Status)
+	}
+}
+
+// keepTransitionTime sets the transition time of the previous status on the current one if they have the same type
+// and reason
+func keepTransitionTime(previous v1alpha1.Status, current *v1alpha1.Status) {
+	if previous.Type == current.Type && previous.Reason == current.Reason && !previous.LastTransitionTime.IsZero() {
+		current.LastTransitionTime = previous.LastTransitionTime
+	}
+}
+
+// setBlueprintConditions sets the Ready, Progressing and Degraded conditions based on the summary
+func setBlueprintConditions(status *v1alpha1.BlueprintStatus, summary *blueprintStatusSummary, reconcileErr error) {
+	ready := metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonAllComponentsAvailable,
+		Message:            "All addons and resources are available",
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	progressing := metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentProgressing),
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonAllComponentsAvailable,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	degraded := metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentDegraded),
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonNoFailures,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	if len(summary.progressing) > 0 {
+		msg := fmt.Sprintf("Waiting for: %s", strings.Join(summary.progressing, ", "))
+		ready.Status, ready.Reason, ready.Message = metav1.ConditionFalse, reasonComponentsProgressing, msg
+		progressing.Status, progressing.Reason, progressing.Message = metav1.ConditionTrue, reasonComponentsProgressing, msg
+	}
+
+	if len(summary.unhealthy) > 0 {
+		msg := fmt.Sprintf("Unhealthy: %s", strings.Join(summary.unhealthy, ", "))
+		ready.Status, ready.Reason, ready.Message = metav1.ConditionFalse, reasonComponentsUnhealthy, msg
+		degraded.Status, degraded.Reason, degraded.Message = metav1.ConditionTrue, reasonComponentsUnhealthy, msg
+	}
+
+	if reconcileErr != nil {
+		ready.Status, ready.Reason, ready.Message = metav1.ConditionFalse, reasonReconcileFailed, reconcileErr.Error()
+		degraded.Status, degraded.Reason, degraded.Message = metav1.ConditionTrue, reasonReconcileFailed, reconcileErr.Error()
+	}
+
+	meta.SetStatusCondition(&status.Conditions, ready)
+	meta.SetStatusCondition(&status.Conditions, progressing)
+	meta.SetStatusCondition(&status.Conditions, degraded)
+}
@@ -0,0 +1,469 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components/certmanager"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components/fluxcd"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/dag"
+)
+
+// fakeComponent is a component whose installed version is updated by Install
+// It is ready once installed, unless notReady is set.
+type fakeComponent struct {
+	name         string
+	desired      string
+	installed    string
+	disabled     bool
+	notReady     bool
+	dependencies []string
+	deployments  []client.ObjectKey
+	objects      []*unstructured.Unstructured
+	installErr   error
+	installs     *[]string
+}
+
+var _ components.Component = &fakeComponent{}
+
+func (c *fakeComponent) Name() string     { return c.name }
+func (c *fakeComponent) Enabled() bool    { return !c.disabled }
+func (c *fakeComponent) Images() []string { return nil }
+
+func (c *fakeComponent) Dependencies() []string { return c.dependencies }
+
+func (c *fakeComponent) Deployments(context.Context) ([]client.ObjectKey, error) {
+	return c.deployments, nil
+}
+
+func (c *fakeComponent) Objects(context.Context) ([]*unstructured.Unstructured, error) {
+	return c.objects, nil
+}
+
+func (c *fakeComponent) DesiredVersion(context.Context) (string, error)   { return c.desired, nil }
+func (c *fakeComponent) InstalledVersion(context.Context) (string, error) { return c.installed, nil }
+func (c *fakeComponent) CheckExists(context.Context) (bool, error)        { return c.installed != "", nil }
+func (c *fakeComponent) Uninstall(context.Context) error                  { return nil }
+
+func (c *fakeComponent) Ready(context.Context) (bool, error) {
+	return c.installed != "" && !c.notReady, nil
+}
+
+func (c *fakeComponent) Install(context.Context) error {
+	*c.installs = append(*c.installs, c.name)
+	if c.installErr != nil {
+		return c.installErr
+	}
+	c.installed = c.desired
+	return nil
+}
+
+var _ = Describe("Installation components", func() {
+	var (
+		r        *InstallationReconciler
+		instance *v1alpha1.Installation
+		installs []string
+	)
+
+	BeforeEach(func() {
+		r = &InstallationReconciler{Client: fake.NewClientBuilder().Build(), Recorder: record.NewFakeRecorder(10)}
+		instance = &v1alpha1.Installation{}
+		installs = nil
+	})
+
+	install := func(specChanged bool, list ...components.Component) error {
+		_, err := r.installComponents(context.TODO(), logr.Discard(), instance, list, specChanged, time.Now())
+		return err
+	}
+
+	It("Should install missing components and record their versions", func() {
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", installs: &installs}
+		webhook := &fakeComponent{name: "webhook", desired: "v1.0.0", installs: &installs}
+
+		Expect(install(false, fluxcd, webhook)).To(Succeed())
+		Expect(installs).To(Equal([]string{"fluxcd", "webhook"}))
+		Expect(instance.Status.Components).To(HaveLen(2))
+		Expect(instance.Status.Components[0].Name).To(Equal("fluxcd"))
+		Expect(instance.Status.Components[0].Version).To(Equal("v2.2.3"))
+		Expect(instance.Status.Components[0].PreviousVersion).To(BeEmpty())
+		Expect(instance.Status.Components[0].LastTransitionTime).NotTo(BeNil())
+	})
+
+	It("Should not install components that are up to date", func() {
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", installed: "v2.2.3", installs: &installs}
+
+		Expect(install(false, fluxcd)).To(Succeed())
+		Expect(installs).To(BeEmpty())
+		Expect(instance.Status.Components).To(HaveLen(1))
+		Expect(instance.Status.Components[0].Version).To(Equal("v2.2.3"))
+	})
+
+	It("Should install components again when the spec has changed", func() {
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", installed: "v2.2.3", installs: &installs}
+
+		Expect(install(true, fluxcd)).To(Succeed())
+		Expect(installs).To(Equal([]string{"fluxcd"}))
+	})
+
+	It("Should upgrade outdated components in order and record the transitions", func() {
+		instance.Status.Components = []v1alpha1.ComponentStatus{
+			{Name: "fluxcd", Version: "v2.2.2"},
+			{Name: "webhook", Version: "v1.0.0"},
+		}
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", installed: "v2.2.2", installs: &installs}
+		webhook := &fakeComponent{name: "webhook", desired: "v1.1.0", installed: "v1.0.0", installs: &installs}
+
+		Expect(install(false, fluxcd, webhook)).To(Succeed())
+		Expect(installs).To(Equal([]string{"fluxcd", "webhook"}))
+		Expect(instance.Status.Components[0].Version).To(Equal("v2.2.3"))
+		Expect(instance.Status.Components[0].PreviousVersion).To(Equal("v2.2.2"))
+		Expect(instance.Status.Components[0].LastTransitionTime).NotTo(BeNil())
+		Expect(instance.Status.Components[1].Version).To(Equal("v1.1.0"))
+		Expect(instance.Status.Components[1].PreviousVersion).To(Equal("v1.0.0"))
+	})
+
+	It("Should stop upgrading at the first component that fails", func() {
+		instance.Status.Components = []v1alpha1.ComponentStatus{
+			{Name: "fluxcd", Version: "v2.2.2"},
+			{Name: "certmanager", Version: "v1.9.0"},
+			{Name: "webhook", Version: "v1.0.0"},
+		}
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", installed: "v2.2.2", installs: &installs}
+		certManager := &fakeComponent{name: "certmanager", desired: "v1.9.1", installed: "v1.9.0", installs: &installs, installErr: errors.New("boom")}
+		webhook := &fakeComponent{name: "webhook", desired: "v1.1.0", installed: "v1.0.0", installs: &installs}
+
+		result, err := r.installComponents(context.TODO(), logr.Discard(), instance, []components.Component{fluxcd, certManager, webhook}, false, time.Now())
+		Expect(err).To(HaveOccurred())
+		Expect(result.failed).To(Equal("certmanager"))
+		Expect(installs).To(Equal([]string{"fluxcd", "certmanager"}))
+		Expect(instance.Status.Components[0].Version).To(Equal("v2.2.3"))
+		Expect(instance.Status.Components[1].Version).To(Equal("v1.9.0"))
+		Expect(instance.Status.Components[2].Version).To(Equal("v1.0.0"))
+	})
+
+	It("Should repair components whose objects drifted", func() {
+		configMap := &unstructured.Unstructured{}
+		configMap.SetAPIVersion("v1")
+		configMap.SetKind("ConfigMap")
+		configMap.SetName("config")
+		configMap.SetNamespace("test")
+		Expect(unstructured.SetNestedField(configMap.Object, "b", "data", "a")).To(Succeed())
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", installed: "v2.2.3", installs: &installs, objects: []*unstructured.Unstructured{configMap}}
+
+		By("Repairing the deleted objects")
+		Expect(install(false, fluxcd)).To(Succeed())
+		Expect(installs).To(Equal([]string{"fluxcd"}))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal("Normal SuccessfulRepair Repaired component fluxcd: ConfigMap test/config (deleted)")))
+
+		By("Leaving the components without drift alone")
+		Expect(r.Client.Create(context.TODO(), configMap.DeepCopy())).To(Succeed())
+		installs = nil
+		Expect(install(false, fluxcd)).To(Succeed())
+		Expect(installs).To(BeEmpty())
+
+		By("Reporting the repairs that failed")
+		live := &corev1.ConfigMap{}
+		Expect(r.Client.Get(context.TODO(), client.ObjectKeyFromObject(configMap), live)).To(Succeed())
+		live.Data["a"] = "c"
+		Expect(r.Client.Update(context.TODO(), live)).To(Succeed())
+		fluxcd.installErr = errors.New("boom")
+		Expect(install(false, fluxcd)).NotTo(Succeed())
+		Expect(installs).To(Equal([]string{"fluxcd"}))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal("Warning FailedRepair Failed to repair component fluxcd: boom")))
+	})
+
+	It("Should repair components whose deployment was deleted", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+		mapper := meta.NewDefaultRESTMapper(nil)
+		r.Client = fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithInterceptorFuncs(interceptor.Funcs{
+			// the CRDs are reported as established and their kinds are discovered, as the API server would do
+			// once they are created, and the deployments are reported as available
+			Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := cl.Get(ctx, key, obj, opts...); err != nil {
+					return err
+				}
+				if d, ok := obj.(*appsv1.Deployment); ok {
+					d.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+				}
+				if u, ok := obj.(*unstructured.Unstructured); ok && u.GetKind() == "CustomResourceDefinition" {
+					conditions := []interface{}{map[string]interface{}{"type": "Established", "status": "True"}}
+					Expect(unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")).To(Succeed())
+					group, _, _ := unstructured.NestedString(u.Object, "spec", "group")
+					kind, _, _ := unstructured.NestedString(u.Object, "spec", "names", "kind")
+					versions, _, _ := unstructured.NestedSlice(u.Object, "spec", "versions")
+					for _, v := range versions {
+						version, _, _ := unstructured.NestedString(v.(map[string]interface{}), "name")
+						mapper.Add(schema.GroupVersionKind{Group: group, Version: version, Kind: kind}, meta.RESTScopeNamespace)
+					}
+				}
+				return nil
+			},
+		}).Build()
+
+		for _, tc := range []struct {
+			component  components.Component
+			deployment client.ObjectKey
+		}{
+			{fluxcd.NewFluxCDComponent(r.Client, logr.Discard(), "", nil), client.ObjectKey{Namespace: "flux-system", Name: "helm-controller"}},
+			{certmanager.NewCertManagerComponent(r.Client, logr.Discard(), "", nil), client.ObjectKey{Namespace: consts.NamespaceBlueprintSystem, Name: "cert-manager"}},
+		} {
+			By("Installing " + tc.component.Name())
+			Expect(install(false, tc.component)).To(Succeed())
+			Expect(componentRecorded(&instance.Status, tc.component.Name())).To(BeTrue())
+
+			By("Repairing " + tc.component.Name() + " once its deployment is deleted")
+			Expect(r.Client.Delete(context.TODO(), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: tc.deployment.Namespace, Name: tc.deployment.Name}})).To(Succeed())
+			exists, err := tc.component.CheckExists(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+
+			Expect(install(false, tc.component)).To(Succeed())
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(fmt.Sprintf(
+				"Normal SuccessfulRepair Repaired component %s: Deployment %s (deleted)", tc.component.Name(), tc.deployment))))
+			Expect(r.Client.Get(context.TODO(), tc.deployment, &appsv1.Deployment{})).To(Succeed())
+		}
+	})
+
+	It("Should skip disabled components", func() {
+		fluxcd := &fakeComponent{name: "fluxcd", desired: "v2.2.3", disabled: true, installs: &installs}
+
+		Expect(install(false, fluxcd)).To(Succeed())
+		Expect(installs).To(BeEmpty())
+		Expect(instance.Status.Components).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Installation conditions", func() {
+	var (
+		instance *v1alpha1.Installation
+		ready    = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "test"},
+			Status: appsv1.DeploymentStatus{
+				Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1,
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+			},
+		}
+		progressing = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "progressing", Namespace: "test"},
+			Status:     appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1},
+		}
+		failed = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "test"},
+			Status: appsv1.DeploymentStatus{
+				Replicas: 1,
+				Conditions: []appsv1.DeploymentCondition{{
+					Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+					Message: "deployment exceeded its progress deadline",
+				}},
+			},
+		}
+	)
+
+	BeforeEach(func() {
+		instance = &v1alpha1.Installation{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	})
+
+	setConditions := func(failedName string, installErr error, list ...components.Component) bool {
+		r := &InstallationReconciler{Client: fake.NewClientBuilder().WithObjects(ready.DeepCopy(), progressing.DeepCopy(), failed.DeepCopy()).Build()}
+		isReady, err := r.setComponentConditions(context.TODO(), instance, list, installResult{failed: failedName}, installErr)
+		Expect(err).NotTo(HaveOccurred())
+		return isReady
+	}
+
+	component := func(name string, deployments ...*appsv1.Deployment) *fakeComponent {
+		c := &fakeComponent{name: name, installed: "v1.0.0"}
+		for _, d := range deployments {
+			c.deployments = append(c.deployments, client.ObjectKeyFromObject(d))
+		}
+		return c
+	}
+
+	expectCondition := func(conditionType string, status metav1.ConditionStatus, reason string) {
+		cond := meta.FindStatusCondition(instance.Status.Conditions, conditionType)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(status))
+		Expect(cond.Reason).To(Equal(reason))
+		Expect(cond.ObservedGeneration).To(Equal(int64(2)))
+	}
+
+	It("Should be ready when the deployments of every component are ready", func() {
+		external := &fakeComponent{name: "external", installed: "v1.0.0"}
+
+		Expect(setConditions("", nil, component("fluxcd", ready), external)).To(BeTrue())
+		expectCondition("fluxcd", metav1.ConditionTrue, reasonComponentReady)
+		expectCondition("external", metav1.ConditionTrue, reasonComponentInstalled)
+		expectCondition("Ready", metav1.ConditionTrue, reasonAllComponentsReady)
+	})
+
+	It("Should not be ready while deployments are progressing", func() {
+		Expect(setConditions("", nil, component("fluxcd", ready, progressing))).To(BeFalse())
+		expectCondition("fluxcd", metav1.ConditionFalse, reasonComponentInstalled)
+		Expect(meta.FindStatusCondition(instance.Status.Conditions, "fluxcd").Message).To(ContainSubstring("progressing (0/1 available)"))
+		expectCondition("Ready", metav1.ConditionFalse, reasonComponentsNotReady)
+	})
+
+	It("Should report missing components as not installed", func() {
+		Expect(setConditions("", nil, &fakeComponent{name: "webhook"})).To(BeFalse())
+		expectCondition("webhook", metav1.ConditionFalse, reasonComponentNotInstalled)
+		expectCondition("Ready", metav1.ConditionFalse, reasonComponentsNotReady)
+	})
+
+	It("Should report components that failed to install or roll out as degraded", func() {
+		isReady := setConditions("cert-manager", errors.New("boom"),
+			component("fluxcd", failed), component("cert-manager", ready), component("webhook", ready))
+
+		Expect(isReady).To(BeFalse())
+		expectCondition("fluxcd", metav1.ConditionFalse, reasonComponentDegraded)
+		expectCondition("cert-manager", metav1.ConditionFalse, reasonComponentDegraded)
+		Expect(meta.FindStatusCondition(instance.Status.Conditions, "cert-manager").Message).To(Equal("boom"))
+		expectCondition("webhook", metav1.ConditionTrue, reasonComponentReady)
+		expectCondition("Ready", metav1.ConditionFalse, reasonComponentsDegraded)
+		Expect(meta.FindStatusCondition(instance.Status.Conditions, "Ready").Message).To(Equal("Degraded components: fluxcd, cert-manager"))
+	})
+
+	It("Should remove the condition of disabled components", func() {
+		instance.Status.Conditions = []metav1.Condition{{Type: "fluxcd", Status: metav1.ConditionTrue, Reason: reasonComponentReady}}
+		fluxcd := component("fluxcd", ready)
+		fluxcd.disabled = true
+
+		Expect(setConditions("", nil, fluxcd)).To(BeTrue())
+		Expect(meta.FindStatusCondition(instance.Status.Conditions, "fluxcd")).To(BeNil())
+	})
+})
+
+var _ = Describe("Installation dependencies", func() {
+	var (
+		r        *InstallationReconciler
+		instance *v1alpha1.Installation
+		installs []string
+	)
+
+	BeforeEach(func() {
+		r = &InstallationReconciler{Client: fake.NewClientBuilder().Build(), Recorder: record.NewFakeRecorder(10)}
+		instance = &v1alpha1.Installation{}
+		installs = nil
+	})
+
+	names := func(list []components.Component) []string {
+		var result []string
+		for _, c := range list {
+			result = append(result, c.Name())
+		}
+		return result
+	}
+
+	It("Should order the components after their dependencies", func() {
+		webhook := &fakeComponent{name: "webhook", dependencies: []string{"cert-manager"}}
+		certManager := &fakeComponent{name: "cert-manager"}
+		fluxcd := &fakeComponent{name: "fluxcd", dependencies: []string{"unknown"}}
+
+		sorted, err := sortComponentsByDependencies([]components.Component{webhook, certManager, fluxcd})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(sorted)).To(Equal([]string{"cert-manager", "webhook", "fluxcd"}))
+	})
+
+	It("Should fail to order components with cyclic dependencies", func() {
+		a := &fakeComponent{name: "a", dependencies: []string{"b"}}
+		b := &fakeComponent{name: "b", dependencies: []string{"a"}}
+
+		_, err := sortComponentsByDependencies([]components.Component{a, b})
+		var cycleErr *dag.CycleError
+		Expect(errors.As(err, &cycleErr)).To(BeTrue())
+	})
+
+	It("Should wait for the dependencies to be ready before installing a component", func() {
+		certManager := &fakeComponent{name: "cert-manager", desired: "v1.9.1", notReady: true, installs: &installs}
+		webhook := &fakeComponent{name: "webhook", desired: "v1.0.0", dependencies: []string{"cert-manager"}, installs: &installs}
+		list := []components.Component{certManager, webhook}
+
+		By("Installing the dependency only")
+		result, err := r.installComponents(context.TODO(), logr.Discard(), instance, list, false, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installs).To(Equal([]string{"cert-manager"}))
+		Expect(result.waiting).To(Equal(map[string][]string{"webhook": {"cert-manager"}}))
+
+		isReady, err := r.setComponentConditions(context.TODO(), instance, list, result, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isReady).To(BeFalse())
+		cond := meta.FindStatusCondition(instance.Status.Conditions, "webhook")
+		Expect(cond.Reason).To(Equal(reasonComponentWaiting))
+		Expect(cond.Message).To(Equal("Waiting for dependencies to be ready: cert-manager"))
+
+		By("Installing the component once the dependency is ready")
+		certManager.notReady = false
+		installs = nil
+		result, err = r.installComponents(context.TODO(), logr.Discard(), instance, list, false, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installs).To(Equal([]string{"webhook"}))
+		Expect(result.waiting).To(BeEmpty())
+	})
+
+	It("Should wait for the dependencies of the dependencies", func() {
+		a := &fakeComponent{name: "a", desired: "v1", notReady: true, installs: &installs}
+		b := &fakeComponent{name: "b", desired: "v1", installed: "v1", dependencies: []string{"a"}, installs: &installs}
+		c := &fakeComponent{name: "c", desired: "v1", dependencies: []string{"b"}, installs: &installs}
+
+		result, err := r.installComponents(context.TODO(), logr.Discard(), instance, []components.Component{a, b, c}, false, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installs).To(Equal([]string{"a"}))
+		Expect(result.waiting).To(Equal(map[string][]string{"b": {"a"}, "c": {"b"}}))
+	})
+
+	It("Should not wait for disabled dependencies", func() {
+		certManager := &fakeComponent{name: "cert-manager", disabled: true, installs: &installs}
+		webhook := &fakeComponent{name: "webhook", desired: "v1.0.0", dependencies: []string{"cert-manager"}, installs: &installs}
+
+		result, err := r.installComponents(context.TODO(), logr.Discard(), instance, []components.Component{certManager, webhook}, false, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installs).To(Equal([]string{"webhook"}))
+		Expect(result.waiting).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Installation watches", func() {
+	object := func(generation int64, labels map[string]string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: generation, Labels: labels}}
+	}
+	labelled := map[string]string{consts.ComponentLabel: "fluxcd"}
+
+	It("Should only consider the objects of the components", func() {
+		Expect(componentObjectPredicate.Delete(ctrlevent.DeleteEvent{Object: object(1, labelled)})).To(BeTrue())
+		Expect(componentObjectPredicate.Delete(ctrlevent.DeleteEvent{Object: object(1, nil)})).To(BeFalse())
+	})
+
+	It("Should ignore the status updates of objects with a generation", func() {
+		update := func(old, new *metav1.PartialObjectMetadata) bool {
+			return componentObjectPredicate.Update(ctrlevent.UpdateEvent{ObjectOld: old, ObjectNew: new})
+		}
+
+		Expect(update(object(1, labelled), object(1, labelled))).To(BeFalse())
+		Expect(update(object(1, labelled), object(2, labelled))).To(BeTrue())
+		Expect(update(object(1, labelled), object(1, map[string]string{consts.ComponentLabel: "fluxcd", "a": "b"}))).To(BeTrue())
+		Expect(update(object(0, labelled), object(0, labelled))).To(BeTrue())
+	})
+})
@@ -2,12 +2,16 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -19,6 +23,7 @@ import (
 	"github.com/mirantiscontainers/blueprint-operator/pkg/components/certmanager"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/components/fluxcd"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/components/webhook"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/event"
 )
 
 var (
@@ -31,6 +36,7 @@ type InstallationReconciler struct {
 	Scheme        *runtime.Scheme
 	SetupLogger   logr.Logger
 	ImageRegistry string
+	Recorder      record.EventRecorder
 }
 
 var installationFinalizer = "blueprint.mirantis.com/installation-finalizer"
@@ -39,12 +45,17 @@ var installationFinalizer = "blueprint.mirantis.com/installation-finalizer"
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=installations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=installations/finalizers,verbs=update
 
-// AllComponents returns a list of components installed by the blueprint operator
-func AllComponents(c client.Client, logger logr.Logger, imageRegistry string) []components.Component {
+// AllComponents returns a list of components installed by the blueprint operator, configured as per the
+// Installation spec. The registry of the spec takes precedence over the image registry of the operator.
+func AllComponents(c client.Client, logger logr.Logger, imageRegistry string, spec v1alpha1.InstallationSpec) []components.Component {
+	if spec.Registry != "" {
+		imageRegistry = spec.Registry
+	}
+
 	return []components.Component{
-		fluxcd.NewFluxCDComponent(c, logger, imageRegistry),
-		certmanager.NewCertManagerComponent(c, logger, imageRegistry),
-		webhook.NewWebhookComponent(c, logger),
+		fluxcd.NewFluxCDComponent(c, logger, imageRegistry, spec.FluxCD),
+		certmanager.NewCertManagerComponent(c, logger, imageRegistry, spec.CertManager),
+		webhook.NewWebhookComponent(c, logger, spec.Webhook),
 	}
 }
 
@@ -66,8 +77,12 @@ func (r *InstallationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
-	// list of components to install
-	componentList := AllComponents(r.Client, logger, r.ImageRegistry)
+	// list of components to install, ordered by dependencies
+	componentList, err := sortComponentsByDependencies(AllComponents(r.Client, logger, r.ImageRegistry, instance.Spec))
+	if err != nil {
+		logger.Error(err, "Failed to order components")
+		return ctrl.Result{}, err
+	}
 
 	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
 		if !controllerutil.ContainsFinalizer(instance, installationFinalizer) {
@@ -80,8 +95,13 @@ func (r *InstallationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	} else {
 		// The object is being deleted
+		// Components are uninstalled in reverse dependency order, so that no component outlives its dependencies
 		logger.Info("Uninstalling components")
-		for _, component := range componentList {
+		for i := len(componentList) - 1; i >= 0; i-- {
+			component := componentList[i]
+			if !component.Enabled() {
+				continue
+			}
 			if err := component.Uninstall(ctx); err != nil {
 				logger.Error(err, "Failed to uninstall component", "Name", component.Name())
 				InstallationHistVec.WithLabelValues(component.Name(), metricsOperationUninstall, metricStatusFailure).Observe(time.Since(start).Seconds())
@@ -100,32 +120,205 @@ func (r *InstallationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
-	// Install components
+	// Install or upgrade components
+	// Installed components are installed again when the spec has changed, so that they are configured as per the spec
+	original := instance.DeepCopy()
+	specChanged := instance.Status.ObservedGeneration != instance.Generation
+	result, installErr := r.installComponents(ctx, logger, instance, componentList, specChanged, start)
+	if installErr == nil && len(result.waiting) == 0 {
+		instance.Status.ObservedGeneration = instance.Generation
+	}
+
+	ready, err := r.setComponentConditions(ctx, instance, componentList, result, installErr)
+	if err != nil {
+		logger.Error(err, "Failed to check the health of components")
+		return ctrl.Result{}, err
+	}
+
+	// the status is updated even if a component failed, so that the upgrades that succeeded are recorded
+	if !equality.Semantic.DeepEqual(original.Status, instance.Status) {
+		if err := r.Status().Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+			logger.Error(err, "Failed to update Installation status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if installErr != nil {
+		logger.Error(installErr, "Failed to install components")
+		return ctrl.Result{}, installErr
+	}
+	if !ready {
+		logger.Info("Components are not ready yet", "Requeue", true)
+		return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, nil
+	}
+	logger.V(1).Info("Finished reconciling Installation")
+	return ctrl.Result{}, nil
+}
+
+// installResult is the outcome of the installation of the components
+type installResult struct {
+	// failed is the name of the component that failed to be installed, if any
+	failed string
+	// waiting maps the components waiting for their dependencies to be ready to these dependencies
+	waiting map[string][]string
+}
+
+// installComponents installs the enabled components in dependency order, and upgrades in place the installed
+// components whose version differs from the version bundled with the operator
+// A component is only installed once its dependencies are ready; components waiting for their dependencies are
+// skipped and are installed by a later reconcile. It stops at the first component that fails, so that a component
+// is never upgraded before the components preceding it. The versions of the components are recorded in the
+// Installation status.
+func (r *InstallationReconciler) installComponents(ctx context.Context, logger logr.Logger, instance *v1alpha1.Installation,
+	componentList []components.Component, specChanged bool, start time.Time) (installResult, error) {
+
+	result := installResult{waiting: map[string][]string{}}
+	byName := map[string]components.Component{}
 	for _, component := range componentList {
+		byName[component.Name()] = component
+	}
+	notReady := map[string]bool{}
+
+	for _, component := range componentList {
+		if !component.Enabled() {
+			logger.Info("Component is disabled", "Name", component.Name())
+			continue
+		}
+
+		pending, err := pendingComponentDependencies(ctx, component, byName, notReady)
+		if err != nil {
+			result.failed = component.Name()
+			return result, err
+		}
+		if len(pending) > 0 {
+			logger.Info("Component is waiting for its dependencies to be ready", "Name", component.Name(), "Dependencies", pending)
+			result.waiting[component.Name()] = pending
+			notReady[component.Name()] = true
+			continue
+		}
+
 		exists, err := component.CheckExists(ctx)
 		if err != nil {
 			logger.Error(err, "failed to check if component already exists", "Name", component.Name())
-			return ctrl.Result{}, err
+			result.failed = component.Name()
+			return result, err
 		}
 
-		if !exists {
-			logger.Info("Component is not installed. Installing...", "Name", component.Name())
-			if err = component.Install(ctx); err != nil {
-				InstallationHistVec.WithLabelValues(component.Name(), metricsOperationInstall, metricStatusFailure).Observe(time.Since(start).Seconds())
-				return ctrl.Result{}, err
+		desired, err := component.DesiredVersion(ctx)
+		if err != nil {
+			result.failed = component.Name()
+			return result, fmt.Errorf("failed to get desired version of component %s: %w", component.Name(), err)
+		}
+		installed, err := component.InstalledVersion(ctx)
+		if err != nil {
+			result.failed = component.Name()
+			return result, fmt.Errorf("failed to get installed version of component %s: %w", component.Name(), err)
+		}
+		// components whose version can't be read, e.g. because one of their deployments was deleted, are repaired
+		// rather than upgraded
+		upgrade := exists && installed != "" && installed != desired
+		// a component that was installed before but no longer exists was deleted, so it is repaired
+		removed := !exists && componentRecorded(&instance.Status, component.Name())
+
+		// the objects of an up-to-date or removed component are checked for drift, so that the component is
+		// repaired if any of its objects was deleted or modified
+		var drifted []string
+		if (exists && !upgrade && !specChanged) || removed {
+			objs, err := component.Objects(ctx)
+			if err != nil {
+				result.failed = component.Name()
+				return result, fmt.Errorf("failed to get objects of component %s: %w", component.Name(), err)
 			}
-			InstallationHistVec.WithLabelValues(component.Name(), metricsOperationInstall, metricStatusSuccess).Observe(time.Since(start).Seconds())
-		} else {
-			logger.Info("Component is already installed", "Name", component.Name())
+			if drifted, err = components.Drift(ctx, r.Client, objs); err != nil {
+				result.failed = component.Name()
+				return result, fmt.Errorf("failed to check drift of component %s: %w", component.Name(), err)
+			}
+		}
+
+		operation := metricsOperationInstall
+		switch {
+		case removed:
+			operation = metricsOperationRepair
+			logger.Info("Component was removed. Repairing...", "Name", component.Name(), "Objects", drifted)
+		case !exists:
+			logger.Info("Component is not installed. Installing...", "Name", component.Name(), "Version", desired)
+		case upgrade:
+			operation = metricsOperationUpgrade
+			logger.Info("Upgrading component", "Name", component.Name(), "From", installed, "To", desired)
+		case specChanged:
+			logger.Info("Installation spec has changed. Installing component again...", "Name", component.Name())
+		case len(drifted) > 0:
+			operation = metricsOperationRepair
+			logger.Info("Component has drifted. Repairing...", "Name", component.Name(), "Objects", drifted)
+		default:
+			logger.Info("Component is already installed", "Name", component.Name(), "Version", installed)
+			setComponentVersion(&instance.Status, component.Name(), installed)
+			continue
+		}
+
+		err = component.Install(ctx)
+		InstallationHistVec.WithLabelValues(component.Name(), operation, getMetricStatus(err)).Observe(time.Since(start).Seconds())
+		if operation == metricsOperationRepair {
+			r.recordRepair(instance, component.Name(), drifted, err)
+		}
+		if err != nil {
+			result.failed = component.Name()
+			return result, fmt.Errorf("failed to %s component %s: %w", operation, component.Name(), err)
 		}
+		setComponentVersion(&instance.Status, component.Name(), desired)
 	}
-	logger.V(1).Info("Finished reconciling Installation")
-	return ctrl.Result{}, nil
+
+	return result, nil
+}
+
+// recordRepair records an event on the Installation reporting the repair of the drifted objects of a component
+func (r *InstallationReconciler) recordRepair(instance *v1alpha1.Installation, name string, drifted []string, err error) {
+	if err != nil {
+		r.Recorder.Eventf(instance, event.TypeWarning, event.ReasonFailedRepair, "Failed to repair component %s: %s", name, err)
+		return
+	}
+	r.Recorder.Eventf(instance, event.TypeNormal, event.ReasonSuccessfulRepair, "Repaired component %s: %s", name, strings.Join(drifted, ", "))
+}
+
+// componentRecorded returns true if a version of the component is recorded in the Installation status, i.e. the
+// component was installed before
+func componentRecorded(status *v1alpha1.InstallationStatus, name string) bool {
+	for _, c := range status.Components {
+		if c.Name == name {
+			return c.Version != ""
+		}
+	}
+	return false
+}
+
+// setComponentVersion records the version of the component in the Installation status
+// When the version changes, the previous version and the time of the transition are recorded as well. Components
+// whose version is not managed by the operator, such as external ones, are not recorded.
+func setComponentVersion(status *v1alpha1.InstallationStatus, name, version string) {
+	if version == "" {
+		return
+	}
+
+	now := metav1.Now()
+	for i := range status.Components {
+		c := &status.Components[i]
+		if c.Name != name {
+			continue
+		}
+		if c.Version != version {
+			c.PreviousVersion, c.Version = c.Version, version
+			c.LastTransitionTime = &now
+		}
+		return
+	}
+
+	status.Components = append(status.Components, v1alpha1.ComponentStatus{Name: name, Version: version, LastTransitionTime: &now})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *InstallationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := ctrl.NewControllerManagedBy(mgr).For(&v1alpha1.Installation{}).Complete(r); err != nil {
+	b := ctrl.NewControllerManagedBy(mgr).For(&v1alpha1.Installation{})
+	if err := watchComponentObjects(b).Complete(r); err != nil {
 		return err
 	}
 
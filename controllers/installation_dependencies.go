@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/dag"
+)
+
+// sortComponentsByDependencies returns the components ordered so that every component comes after its dependencies
+// Dependencies on components that are not part of the list are not part of the ordering.
+func sortComponentsByDependencies(list []components.Component) ([]components.Component, error) {
+	byName := map[string]components.Component{}
+	for _, component := range list {
+		byName[component.Name()] = component
+	}
+
+	graph := dag.New()
+	for _, component := range list {
+		graph.AddNode(component.Name())
+		for _, dep := range component.Dependencies() {
+			if _, ok := byName[dep]; ok {
+				graph.AddNode(component.Name(), dep)
+			}
+		}
+	}
+
+	order, err := graph.TopologicalSort()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order components by dependencies: %w", err)
+	}
+
+	sorted := make([]components.Component, 0, len(order))
+	for _, name := range order {
+		sorted = append(sorted, byName[name])
+	}
+	return sorted, nil
+}
+
+// pendingComponentDependencies returns the dependencies of the component that are not ready yet
+// Components in notReady are considered not ready without checking them, because they are waiting for their own
+// dependencies. Disabled components are not waited for, as they are not managed by the operator.
+func pendingComponentDependencies(ctx context.Context, component components.Component, byName map[string]components.Component, notReady map[string]bool) ([]string, error) {
+	var pending []string
+	for _, name := range component.Dependencies() {
+		dep, ok := byName[name]
+		if !ok || !dep.Enabled() {
+			continue
+		}
+		if notReady[name] {
+			pending = append(pending, name)
+			continue
+		}
+
+		ready, err := dep.Ready(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if component %s is ready: %w", name, err)
+		}
+		if !ready {
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
+)
+
+const (
+	// reasonComponentReady is used when all the deployments of a component are ready
+	reasonComponentReady = "Ready"
+	// reasonComponentInstalled is used when a component is installed but its deployments are not ready yet
+	reasonComponentInstalled = "Installed"
+	// reasonComponentDegraded is used when a component failed to be installed or its deployments failed to roll out
+	reasonComponentDegraded = "Degraded"
+	// reasonComponentNotInstalled is used when a component is not installed yet
+	reasonComponentNotInstalled = "NotInstalled"
+	// reasonComponentWaiting is used when a component is waiting for its dependencies to be ready to be installed
+	reasonComponentWaiting = "WaitingForDependencies"
+	// reasonAllComponentsReady is used when every enabled component is ready
+	reasonAllComponentsReady = "AllComponentsReady"
+	// reasonComponentsNotReady is used when one or more components are not ready yet
+	reasonComponentsNotReady = "ComponentsNotReady"
+	// reasonComponentsDegraded is used when one or more components are degraded
+	reasonComponentsDegraded = "ComponentsDegraded"
+)
+
+// setComponentConditions sets a condition named after each enabled component reporting its health, and the
+// aggregate Ready condition of the Installation
+// The failed component, if any, is reported as degraded with the error it failed with, and the components that are
+// not ready while waiting for their dependencies report the dependencies. It returns true if every enabled component
+// is ready.
+func (r *InstallationReconciler) setComponentConditions(ctx context.Context, instance *v1alpha1.Installation,
+	componentList []components.Component, result installResult, installErr error) (bool, error) {
+
+	var notReady, degraded []string
+	for _, component := range componentList {
+		if !component.Enabled() {
+			meta.RemoveStatusCondition(&instance.Status.Conditions, component.Name())
+			continue
+		}
+
+		var cond metav1.Condition
+		if component.Name() == result.failed {
+			cond = metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonComponentDegraded, Message: installErr.Error()}
+		} else {
+			var err error
+			if cond, err = r.componentCondition(ctx, component); err != nil {
+				return false, err
+			}
+			if pending, ok := result.waiting[component.Name()]; ok && cond.Status != metav1.ConditionTrue {
+				msg := fmt.Sprintf("Waiting for dependencies to be ready: %s", strings.Join(pending, ", "))
+				cond = metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonComponentWaiting, Message: msg}
+			}
+		}
+		cond.Type = component.Name()
+		cond.ObservedGeneration = instance.Generation
+		meta.SetStatusCondition(&instance.Status.Conditions, cond)
+
+		switch {
+		case cond.Reason == reasonComponentDegraded:
+			degraded = append(degraded, component.Name())
+		case cond.Status != metav1.ConditionTrue:
+			notReady = append(notReady, component.Name())
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:               string(v1alpha1.TypeComponentReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonAllComponentsReady,
+		Message:            "All components are ready",
+		ObservedGeneration: instance.Generation,
+	}
+	switch {
+	case len(degraded) > 0:
+		ready.Status, ready.Reason = metav1.ConditionFalse, reasonComponentsDegraded
+		ready.Message = fmt.Sprintf("Degraded components: %s", strings.Join(degraded, ", "))
+	case len(notReady) > 0:
+		ready.Status, ready.Reason = metav1.ConditionFalse, reasonComponentsNotReady
+		ready.Message = fmt.Sprintf("Components not ready: %s", strings.Join(notReady, ", "))
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, ready)
+
+	return ready.Status == metav1.ConditionTrue, nil
+}
+
+// componentCondition returns the condition reporting the health of an enabled component from its deployments
+// Components without deployments managed by the operator, such as external ones, are ready once installed.
+func (r *InstallationReconciler) componentCondition(ctx context.Context, component components.Component) (metav1.Condition, error) {
+	exists, err := component.CheckExists(ctx)
+	if err != nil {
+		return metav1.Condition{}, fmt.Errorf("failed to check if component %s exists: %w", component.Name(), err)
+	}
+	if !exists {
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonComponentNotInstalled, Message: "Component is not installed"}, nil
+	}
+
+	deployments, err := component.Deployments(ctx)
+	if err != nil {
+		return metav1.Condition{}, fmt.Errorf("failed to get the deployments of component %s: %w", component.Name(), err)
+	}
+	if len(deployments) == 0 {
+		return metav1.Condition{Status: metav1.ConditionTrue, Reason: reasonComponentInstalled, Message: "Component is installed"}, nil
+	}
+
+	health, err := components.CheckDeployments(ctx, r.Client, deployments)
+	if err != nil {
+		return metav1.Condition{}, fmt.Errorf("failed to check the deployments of component %s: %w", component.Name(), err)
+	}
+	switch {
+	case health.Degraded():
+		msg := fmt.Sprintf("Deployments failed to roll out: %s", strings.Join(health.Failed, ", "))
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonComponentDegraded, Message: msg}, nil
+	case !health.Ready():
+		msg := fmt.Sprintf("Waiting for deployments to be ready: %s", strings.Join(health.NotReady, ", "))
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonComponentInstalled, Message: msg}, nil
+	}
+	return metav1.Condition{Status: metav1.ConditionTrue, Reason: reasonComponentReady, Message: "All deployments are ready"}, nil
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+// componentKinds are the kinds of the objects rendered by the components, which are watched to repair the
+// components when their objects are deleted or modified
+// Custom resources, such as the certificates of the webhook, are not watched, as their CRDs may not exist when the
+// operator starts. They are still repaired along with the other objects of their component.
+var componentKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "ResourceQuota"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "", Version: "v1", Kind: "ServiceAccount"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+}
+
+// watchComponentObjects registers a watch on each kind of the objects of the components
+// Only the metadata of the objects is cached, which is enough to be notified of any change to the objects.
+func watchComponentObjects(b *builder.Builder) *builder.Builder {
+	for _, gvk := range componentKinds {
+		metadata := &metav1.PartialObjectMetadata{}
+		metadata.SetGroupVersionKind(gvk)
+		b = b.Watches(
+			metadata,
+			handler.EnqueueRequestsFromMapFunc(findInstallation),
+			builder.WithPredicates(componentObjectPredicate),
+		)
+	}
+	return b
+}
+
+// componentObjectPredicate filters the events of the objects labelled as belonging to a component
+// Updates of objects that have a generation, such as deployments, are only considered when their spec, labels or
+// annotations change, so that the status updates of a rollout don't trigger a drift check each.
+var componentObjectPredicate = predicate.Funcs{
+	CreateFunc: func(e ctrlevent.CreateEvent) bool {
+		return isComponentObject(e.Object)
+	},
+	DeleteFunc: func(e ctrlevent.DeleteEvent) bool {
+		return isComponentObject(e.Object)
+	},
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		if !isComponentObject(e.ObjectOld) && !isComponentObject(e.ObjectNew) {
+			return false
+		}
+		if e.ObjectNew.GetGeneration() == 0 {
+			return true
+		}
+		return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+			!reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+			!reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations())
+	},
+	GenericFunc: func(e ctrlevent.GenericEvent) bool {
+		return isComponentObject(e.Object)
+	},
+}
+
+// isComponentObject returns true if the object is labelled as belonging to a component
+func isComponentObject(obj client.Object) bool {
+	_, ok := obj.GetLabels()[consts.ComponentLabel]
+	return ok
+}
+
+// findInstallation returns a reconcile request for the Installation, which installs all the components
+func findInstallation(_ context.Context, _ client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: DefaultInstanceKey}}
+}
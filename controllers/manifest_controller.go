@@ -1,31 +1,25 @@
 package controllers
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"io"
-	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
-	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/predicate"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	pkgmanifest "github.com/mirantiscontainers/blueprint-operator/pkg/controllers/manifest"
@@ -34,25 +28,20 @@ import (
 	"github.com/mirantiscontainers/blueprint-operator/pkg/kustomize"
 )
 
-const (
-	manifestUpdateIndex = "manifestupdateindex"
-)
-
 // ManifestReconciler reconciles a Manifest object
 type ManifestReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	watcher *manifestWatcher
 }
 
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=manifests,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=manifests/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=blueprint.mirantis.com,resources=manifests/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
-//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
-//+kubebuilder:rbac:groups=apps,resources=deployments/status,verbs=get
-//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
-//+kubebuilder:rbac:groups=apps,resources=daemonsets/status,verbs=get
+//+kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -102,13 +91,12 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				return ctrl.Result{}, err
 			}
 			logger.Info("finalizer added successfully", "Name", req.Name, "Finalizer", finalizerName)
-			return ctrl.Result{}, nil
 		}
 	} else {
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(instance, finalizerName) {
 			// The finalizer is present, so let's delete the objects for this manifest
-			if err = r.DeleteManifestObjects(ctx, instance.Spec.Objects); err != nil {
+			if err = r.DeleteManifestObjects(ctx, instance.Status.Objects); err != nil {
 				logger.Error(err, "failed to delete manifest objects")
 				r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedDelete, "failed to delete manifest objects %s/%s", instance.Namespace, instance.Name)
 				r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to delete manifest objects", fmt.Sprintf("failed to delete manifest objects : %s", err))
@@ -129,298 +117,350 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
-	if instance.Spec.Checksum == instance.Spec.NewChecksum {
-		logger.Info("checksum is same, no update needed", "Checksum", instance.Spec.Checksum, "NewChecksum", instance.Spec.NewChecksum)
-
-		if pkgmanifest.ShouldRetryManifest(logger, instance) {
-			logger.Info("Reapplying manifest")
-			// wipe the manifest checksum to get reconcile to run an Update
-			instance.Spec.Checksum = ""
-			if err = r.Update(ctx, instance); err != nil {
-				logger.Error(err, "failed to wipe checksum for manifest")
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{}, nil
+	if instance.Status.ObservedGeneration != instance.Generation {
+		// The spec has changed since it was last applied, either because the manifest is new or
+		// because the url, values or the content behind the url have changed.
+		operationType := v1alpha1.ManifestOperationUpgrade
+		if instance.Status.ObservedGeneration == 0 {
+			operationType = v1alpha1.ManifestOperationInstall
 		}
+		logger.Info("manifest spec changed, applying manifest", "Generation", instance.Generation, "ObservedGeneration", instance.Status.ObservedGeneration, "Operation", operationType)
 
-		// manifest is already installed as specified - update manifest status from status's of objects in the cluster
-		if err = r.updateManifestStatus(ctx, logger, req.NamespacedName, instance.Spec.Objects); err != nil {
-			logger.Error(err, "failed to update manifest status")
-			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to update manifest status %s/%s : %s", instance.Namespace, instance.Name, err.Error())
-			r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to update manifest status", fmt.Sprintf("failed to update manifest status : %s", err))
+		var op *v1alpha1.ManifestOperation
+		op, err = pkgmanifest.NewOperation(operationType, instance.Spec.Timeout, 1, time.Now())
+		if err != nil {
+			logger.Error(err, "failed to parse timeout for manifest", "Timeout", instance.Spec.Timeout)
+			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to parse timeout for the manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
-	}
 
-	if (instance.Spec.Checksum != instance.Spec.NewChecksum) && (instance.Spec.NewChecksum != "") {
-		// Update is required
-		logger.Info("checksum differs, update needed", "Checksum", instance.Spec.Checksum, "NewChecksum", instance.Spec.NewChecksum)
-		// First, update the checksum to avoid any reconciliation
-		// Update the CRD
-		// @todo (Ranyodh): The CRD should also add finalizer (or do a Patch() update), otherwise, the finalizer will be removed
-		updatedCRD := v1alpha1.Manifest{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            instance.Name,
-				Namespace:       instance.Namespace,
-				ResourceVersion: instance.ResourceVersion,
-			},
-			Spec: v1alpha1.ManifestSpec{
-				Url:           instance.Spec.Url,
-				Checksum:      instance.Spec.NewChecksum,
-				NewChecksum:   instance.Spec.NewChecksum,
-				FailurePolicy: instance.Spec.FailurePolicy,
-				Timeout:       instance.Spec.Timeout,
-				Values:        instance.Spec.Values,
-			},
-		}
-
-		// @TODO Ranyodh: The update to CRD here will trigger a new reconcile, while this current reconcile will continue to process
-		// This will cause errors as the manifest is already has been updated.
-		// Also, the call to  UpdateManifestObjects() after this causes the CRD to be updated again.
-		// There should be only one reconcile for the update of the manifest. This needs to be fixed.
-		if err = r.Update(ctx, &updatedCRD); err != nil {
-			logger.Error(err, "failed to update manifest crd while update operation")
-			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to update manifest resource while update operation %s/%s : %s", instance.Namespace, instance.Name, err.Error())
-			r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to update manifest crd while update operation ", fmt.Sprintf("failed to update manifest crd while update operation  : %s", err))
+		if err = r.applyManifest(ctx, logger, instance, op); err != nil {
+			logger.Error(err, "failed to apply manifest", "Name", req.Name)
+			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to apply manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
+			r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to apply manifest", err.Error())
 			return ctrl.Result{}, err
 		}
 
-		// TODO: https://github.com/mirantiscontainers/blueprint-operator/pull/17#pullrequestreview-1754136032
-		if err = r.UpdateManifestObjects(req, ctx, instance); err != nil {
-			logger.Error(err, "failed to update manifest")
-			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to update manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
-			r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to update manifest ", fmt.Sprintf("failed to update manifest  : %s", err))
-			return ctrl.Result{}, err
+		r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeNormal, event.ReasonSuccessfulCreate, "Applied Manifest %s/%s", instance.Namespace, instance.Name)
+		if op.Deadline == nil {
+			return ctrl.Result{}, nil
 		}
+		return ctrl.Result{RequeueAfter: time.Until(op.Deadline.Time)}, nil
+	}
 
-		if instance.Spec.Timeout != "" && instance.Spec.FailurePolicy == pkgmanifest.FailurePolicyRetry {
-			var timeoutDuration time.Duration
-			timeoutDuration, err = time.ParseDuration(instance.Spec.Timeout)
-			if err != nil {
-				logger.Error(err, "failed to parse timeout for manifest", "Timeout", timeoutDuration)
-				r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to parse timeout for the manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
-				return ctrl.Result{}, err
+	if len(instance.Status.Failures) > 0 {
+		// only the objects that failed to be applied when the manifest was last applied are applied again
+		if err = r.retryFailedObjects(ctx, logger, instance); err != nil {
+			logger.Error(err, "failed to apply manifest objects")
+			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to apply manifest objects %s/%s : %s", instance.Namespace, instance.Name, err.Error())
+			r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to apply manifest objects", err.Error())
+
+			// the timeout and failure policy also apply to objects that keep failing
+			if _, opErr := r.reconcileOperation(ctx, logger, req); opErr != nil {
+				logger.Error(opErr, "failed to reconcile manifest operation")
 			}
-			go r.retryUpgradeInstallAfterTimeout(ctx, logger, types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, timeoutDuration, instance.Spec.FailurePolicy, false)
+			return ctrl.Result{}, err
 		}
 	}
 
-	if instance.Spec.NewChecksum == "" {
-		// We will reach here only in case of create request.
-		// First, update the checksum in CRD to avoid any reconciliations.
-		// Update the CRD
-		// @todo (Ranyodh): The CRD should also add finalizer (or do a Patch() update), otherwise, the finalizer will be removed
-		updatedCRD := v1alpha1.Manifest{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            instance.Name,
-				Namespace:       instance.Namespace,
-				ResourceVersion: instance.ResourceVersion,
-			},
-			Spec: v1alpha1.ManifestSpec{
-				Url:           instance.Spec.Url,
-				Checksum:      instance.Spec.Checksum,
-				NewChecksum:   instance.Spec.Checksum,
-				Timeout:       instance.Spec.Timeout,
-				FailurePolicy: instance.Spec.FailurePolicy,
-				Values:        instance.Spec.Values,
-			},
-		}
+	// the inventory may contain kinds that are not watched yet, e.g. after the operator restarted
+	if err = r.watchManifestObjects(logger, instance.Status.Objects); err != nil {
+		logger.Error(err, "failed to watch manifest objects")
+		return ctrl.Result{}, err
+	}
 
-		// @TODO Ranyodh: The update to CRD here will trigger a new reconcile. We must exit the reconciler after this.
-		if err = r.Update(ctx, &updatedCRD); err != nil {
-			logger.Error(err, "failed to update manifest crd while create operation")
-			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to update manifest crd while create operation %s/%s : %s", instance.Namespace, instance.Name, err.Error())
-			return ctrl.Result{}, err
-		}
+	// manifest is already applied as specified - update manifest status from status's of objects in the cluster
+	if err = r.updateManifestStatus(ctx, logger, req.NamespacedName, instance.Status.Objects); err != nil {
+		logger.Error(err, "failed to update manifest status")
+		r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to update manifest status %s/%s : %s", instance.Namespace, instance.Name, err.Error())
+		r.updateStatus(ctx, logger, key, v1alpha1.TypeComponentUnhealthy, "failed to update manifest status", fmt.Sprintf("failed to update manifest status : %s", err))
+		return ctrl.Result{}, err
+	}
 
-		// Create the kustomize file, get kustomize build output and create objects thereby.
-		var bodyBytes []byte
-		bodyBytes, err = kustomize.Render(logger, instance.Spec.Url, instance.Spec.Values)
+	// enforce the timeout and failure policy of the last install or upgrade
+	var result ctrl.Result
+	result, err = r.reconcileOperation(ctx, logger, req)
+	return result, err
+}
 
-		if err != nil {
-			logger.Error(err, "failed to fetch manifest file content for url: %s", "Manifest Url", instance.Spec.Url)
-			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to fetch manifest file content for url %s/%s : %s", instance.Namespace, instance.Name, err.Error())
-			return ctrl.Result{}, err
-		}
+// reconcileOperation enforces the timeout and failure policy of the operation recorded in the manifest status
+// Since the operation and its deadline are persisted, the timeout is enforced by requeueing the manifest
+// at the deadline, which survives operator restarts and leader changes.
+func (r *ManifestReconciler) reconcileOperation(ctx context.Context, logger logr.Logger, req ctrl.Request) (ctrl.Result, error) {
+	instance := &v1alpha1.Manifest{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	action, remaining := pkgmanifest.CheckOperation(instance, now)
+	op := instance.Status.Operation.DeepCopy()
+
+	switch action {
+	case pkgmanifest.OperationActionWait:
+		logger.Info("manifest operation in progress", "Operation", op.Type, "Attempts", op.Attempts, "Deadline", op.Deadline)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+
+	case pkgmanifest.OperationActionComplete:
+		logger.Info("manifest operation completed", "Operation", op.Type, "Attempts", op.Attempts)
+		completionTime := metav1.NewTime(now)
+		op.CompletionTime = &completionTime
+		return ctrl.Result{}, r.updateOperation(ctx, logger, req.NamespacedName, op)
+
+	case pkgmanifest.OperationActionFail:
+		failure := fmt.Sprintf("%s timed out after %s: %s", op.Type, op.Deadline.Sub(op.StartTime.Time), instance.Status.Reason)
+		logger.Info("manifest operation timed out", "Operation", op.Type, "Attempts", op.Attempts, "FailurePolicy", instance.Spec.FailurePolicy)
+		r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "manifest %s/%s : %s", instance.Namespace, instance.Name, failure)
 
-		logger.Info("received new crd request. Creating manifest objects..")
-		err = r.CreateManifestObjects(ctx, key, logger, bodyBytes)
+		failureTime := metav1.NewTime(now)
+		op.LastFailure = failure
+		op.LastFailureTime = &failureTime
+		op.CompletionTime = &failureTime
+		return ctrl.Result{}, r.updateOperation(ctx, logger, req.NamespacedName, op)
+
+	case pkgmanifest.OperationActionRetry:
+		failure := fmt.Sprintf("%s timed out after %s: %s", op.Type, op.Deadline.Sub(op.StartTime.Time), instance.Status.Reason)
+		logger.Info("manifest operation timed out, retrying", "Operation", op.Type, "Attempts", op.Attempts)
+		r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "manifest %s/%s : %s, retrying", instance.Namespace, instance.Name, failure)
+
+		next, err := pkgmanifest.NewOperation(op.Type, instance.Spec.Timeout, op.Attempts+1, now)
 		if err != nil {
-			logger.Error(err, "failed to create objects for the manifest", "Name", req.Name)
-			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to create objects for the manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
 			return ctrl.Result{}, err
 		}
+		next.LastFailure = failure
+		next.LastFailureTime = &next.StartTime
 
-		if instance.Spec.Timeout != "" && instance.Spec.FailurePolicy != pkgmanifest.FailurePolicyNone {
-			var timeoutDuration time.Duration
-			timeoutDuration, err = time.ParseDuration(instance.Spec.Timeout)
-			if err != nil {
-				logger.Error(err, "failed to parse timeout for manifest", "Timeout", timeoutDuration)
-				r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to parse timeout for the manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
+		if op.Type == v1alpha1.ManifestOperationInstall {
+			// if it's an install then delete existing manifest objects so they can be fully re-installed
+			logger.Info("Deleting manifest objects", "ManifestName", instance.Name)
+			if err = r.DeleteManifestObjects(ctx, instance.Status.Objects); err != nil {
+				logger.Error(err, "Failed to delete manifest objects")
 				return ctrl.Result{}, err
 			}
-			go r.retryUpgradeInstallAfterTimeout(ctx, logger, types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, timeoutDuration, instance.Spec.FailurePolicy, true)
 		}
+
+		if err = r.applyManifest(ctx, logger, instance, next); err != nil {
+			logger.Error(err, "failed to reapply manifest")
+			r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFailedCreate, "failed to reapply manifest %s/%s : %s", instance.Namespace, instance.Name, err.Error())
+			return ctrl.Result{}, err
+		}
+
+		if next.Deadline == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Until(next.Deadline.Time)}, nil
 	}
-	r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeNormal, event.ReasonSuccessfulCreate, "Created Manifest %s/%s", instance.Namespace, instance.Name)
+
 	return ctrl.Result{}, nil
 }
 
-// retryUpgradeInstallAfterTimeout checks if the manifest is Available after Timeout, and if it is not then it retries the upgrade/install.
-func (r *ManifestReconciler) retryUpgradeInstallAfterTimeout(ctx context.Context, logger logr.Logger, manifestName types.NamespacedName, timeout time.Duration, failurePolicy string, isInstall bool) {
+// updateOperation sets the operation in the status of the manifest with the provided namespacedName
+func (r *ManifestReconciler) updateOperation(ctx context.Context, logger logr.Logger, namespacedName types.NamespacedName, op *v1alpha1.ManifestOperation) error {
+	manifest := &v1alpha1.Manifest{}
+	if err := r.Get(ctx, namespacedName, manifest); err != nil {
+		logger.Error(err, "Failed to get manifest to update operation")
+		return err
+	}
 
-	mc := pkgmanifest.NewManifestController(r.Client, logger)
-	timeoutErr := mc.AwaitTimeout(logger, manifestName, timeout)
-	if timeoutErr != nil {
-		// manifest is not available before timeout
-		var manifest v1alpha1.Manifest
-		err := r.Get(ctx, manifestName, &manifest)
-		if err != nil {
-			logger.Error(err, "Failed to get manifest")
-			return
-		}
+	patch := client.MergeFrom(manifest.DeepCopy())
+	manifest.Status.Operation = op
+	return r.Status().Patch(ctx, manifest, patch)
+}
 
-		r.Recorder.AnnotatedEventf(&manifest, map[string]string{event.AddonAnnotationKey: manifest.Name}, event.TypeWarning, event.ReasonFailedCreate, "manifest creation timed out %s/%s : %s", manifest.Namespace, manifest.Name, timeoutErr.Error())
+// SetupWithManager sets up the controller with the Manager.
+func (r *ManifestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// attaches an index onto the Manifest
+	// This is done, so we can later easily find the manifest associated with a particular object of its inventory
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Manifest{}, manifestObjectIndex, indexManifestObjects); err != nil {
+		return err
+	}
 
-		if isInstall {
-			// if it's an install then delete existing manifest objects so they can be fully re-installed
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Manifest{}).
+		Build(r)
+	if err != nil {
+		return err
+	}
 
-			logger.Info("Deleting manifest objects ", "ManifestName", manifestName)
-			err = r.DeleteManifestObjects(ctx, manifest.Spec.Objects)
-			if err != nil {
-				logger.Error(err, "Failed to delete manifest objects")
-				return
-			}
+	// the kinds of the manifest objects are watched as they are added to the manifest inventories
+	r.watcher = &manifestWatcher{
+		controller:    c,
+		cache:         mgr.GetCache(),
+		findManifests: r.findAssociatedManifests,
+		watched:       map[schema.GroupVersionKind]bool{},
+	}
+	return nil
+}
 
-		}
+// watchManifestObjects makes sure changes to the provided manifest objects trigger a reconcile of their manifest
+func (r *ManifestReconciler) watchManifestObjects(logger logr.Logger, objects []v1alpha1.ManifestObject) error {
+	if r.watcher == nil {
+		return nil
+	}
+	return r.watcher.watch(logger, objects)
+}
 
-		// wipe the manifest checksum to get reconcile to run an Update
-		manifest.Spec.Checksum = ""
-		if err = r.Update(ctx, &manifest); err != nil {
-			logger.Error(err, "failed to wipe checksum for manifest")
-		}
-		return
+// applyManifest renders the manifest and applies its objects in the cluster, deleting the objects that are no
+// longer part of the manifest. It then records the applied generation, the checksum of the rendered manifest,
+// the object inventory, the objects that failed to be applied and the operation in a single update of the
+// manifest status. An error listing the failed objects is returned if any object failed to be applied.
+func (r *ManifestReconciler) applyManifest(ctx context.Context, logger logr.Logger, instance *v1alpha1.Manifest, op *v1alpha1.ManifestOperation) error {
+	data, objs, err := r.renderManifest(logger, instance)
+	if err != nil {
+		return err
+	}
+
+	conflicts, failures, err := r.ApplyManifestObjects(ctx, logger, objs)
+	if err != nil {
+		return err
+	}
+	r.recordConflicts(instance, conflicts)
+
+	var objects []v1alpha1.ManifestObject
+	for _, o := range objs {
+		objects = append(objects, toManifestObject(o))
+	}
+
+	// Find the intersection of the new manifest based
+	// objects and old manifest based objects and delete the extra.
+	r.findAndDeleteObsoleteObjects(ctx, instance.Status.Objects, objects)
+
+	patch := client.MergeFrom(instance.DeepCopy())
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.AppliedChecksum = pkgmanifest.Checksum(data)
+	instance.Status.Objects = objects
+	instance.Status.Conflicts = conflicts
+	instance.Status.Failures = failures
+	instance.Status.Operation = op
+	if err = r.Status().Patch(ctx, instance, patch); err != nil {
+		logger.Error(err, "failed to update manifest status with objectList")
+		return err
 	}
 
-	logger.Info("Manifest is Available before Timeout", "ManifestName", manifestName)
+	if err = r.watchManifestObjects(logger, instance.Status.Objects); err != nil {
+		logger.Error(err, "failed to watch manifest objects")
+		return err
+	}
+
+	return failuresError(failures)
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *ManifestReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// attaches an index onto the Manifest
-	// This is done, so we can later easily find the addon associated with a particular deployment or daemonset
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Manifest{}, manifestUpdateIndex, func(rawObj client.Object) []string {
-		manifest := rawObj.(*v1alpha1.Manifest)
-		if manifest.Spec.Objects == nil || len(manifest.Spec.Objects) == 0 {
-			return nil
-		}
+// retryFailedObjects applies again the objects that failed to be applied when the manifest was last applied
+// The objects that still fail are recorded in the manifest status, and an error listing them is returned.
+func (r *ManifestReconciler) retryFailedObjects(ctx context.Context, logger logr.Logger, instance *v1alpha1.Manifest) error {
+	_, objs, err := r.renderManifest(logger, instance)
+	if err != nil {
+		return err
+	}
 
-		var indexes []string
-		for _, obj := range manifest.Spec.Objects {
-			if obj.Kind == "DaemonSet" || obj.Kind == "Deployment" {
-				indexes = append(indexes, fmt.Sprintf("%s-%s", obj.Namespace, obj.Name))
-			}
+	failed := make(map[string]bool, len(instance.Status.Failures))
+	for _, f := range instance.Status.Failures {
+		failed[inventoryKey(f.ManifestObject)] = true
+	}
+	var retry []*unstructured.Unstructured
+	for _, o := range objs {
+		if failed[inventoryKey(toManifestObject(o))] {
+			retry = append(retry, o)
 		}
-		return indexes
+	}
+	logger.Info("retrying failed manifest objects", "Failed", len(instance.Status.Failures), "Retrying", len(retry))
 
-	}); err != nil {
+	conflicts, failures, err := r.ApplyManifestObjects(ctx, logger, retry)
+	if err != nil {
 		return err
 	}
+	r.recordConflicts(instance, conflicts)
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Manifest{}).
-		Watches(
-			&appsv1.DaemonSet{},
-			handler.EnqueueRequestsFromMapFunc(r.findAssociatedManifest),
-			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
-		).
-		Watches(
-			&appsv1.Deployment{},
-			handler.EnqueueRequestsFromMapFunc(r.findAssociatedManifest),
-			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
-		).
-		Complete(r)
+	patch := client.MergeFrom(instance.DeepCopy())
+	instance.Status.Conflicts = conflicts
+	instance.Status.Failures = failures
+	if err = r.Status().Patch(ctx, instance, patch); err != nil {
+		logger.Error(err, "failed to update manifest status with failed objects")
+		return err
+	}
+
+	return failuresError(failures)
 }
 
-// findAssociatedManifest finds the manifest tied to a particular object if one exists
-// This is done by looking for the manifest that was previously indexed in the form objectNamespace-objectName
-func (r *ManifestReconciler) findAssociatedManifest(ctx context.Context, obj client.Object) []reconcile.Request {
-	attachedManifestList := &v1alpha1.ManifestList{}
-	//TODO: this index will clash if we have multiple deployments / daemonsets with the same name and namespace
-	err := r.List(context.TODO(), attachedManifestList, client.MatchingFields{manifestUpdateIndex: fmt.Sprintf("%s-%s", obj.GetNamespace(), obj.GetName())})
+// renderManifest renders the manifest and decodes its objects
+func (r *ManifestReconciler) renderManifest(logger logr.Logger, instance *v1alpha1.Manifest) ([]byte, []*unstructured.Unstructured, error) {
+	// Create the kustomize file, get kustomize build output and decode the objects.
+	data, err := kustomize.Render(logger, instance.Spec.Url, instance.Spec.Values)
 	if err != nil {
-		return []reconcile.Request{}
+		logger.Error(err, "failed to fetch manifest file content for url: %s", "Manifest Url", instance.Spec.Url)
+		return nil, nil, fmt.Errorf("failed to fetch manifest file content for url %s: %w", instance.Spec.Url, err)
 	}
 
-	requests := make([]reconcile.Request, len(attachedManifestList.Items))
-	for i, item := range attachedManifestList.Items {
-		requests[i] = reconcile.Request{
-			NamespacedName: types.NamespacedName{
-				Name:      item.GetName(),
-				Namespace: item.GetNamespace(),
-			},
-		}
+	objs, err := kubernetes.NewManifestReader(data).ReadManifest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode objects: %w", err)
+	}
+
+	return data, objs, nil
+}
+
+// recordConflicts emits an event for each object that was not applied because its fields are owned by other field managers
+func (r *ManifestReconciler) recordConflicts(instance *v1alpha1.Manifest, conflicts []v1alpha1.ManifestConflict) {
+	for _, c := range conflicts {
+		r.Recorder.AnnotatedEventf(instance, map[string]string{event.AddonAnnotationKey: instance.Name}, event.TypeWarning, event.ReasonFieldConflict, "manifest %s/%s can't apply %s %s/%s as its fields are owned by other field managers : %s", instance.Namespace, instance.Name, c.Kind, c.Namespace, c.Name, strings.Join(c.Fields, ", "))
 	}
-	return requests
 }
 
-// CreateManifestObjects reads manifest from a url and then create all objects in the cluster
-func (r *ManifestReconciler) CreateManifestObjects(ctx context.Context, manifestNamespacedName types.NamespacedName, logger logr.Logger, data []byte) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+// ApplyManifestObjects applies the objects of a manifest in the cluster with server-side apply
+// All objects are applied even if some of them fail. It returns the objects with fields owned by other field
+// managers and the objects that failed to be applied, which include the conflicting ones.
+func (r *ManifestReconciler) ApplyManifestObjects(ctx context.Context, logger logr.Logger, objs []*unstructured.Unstructured) ([]v1alpha1.ManifestConflict, []v1alpha1.ManifestObjectFailure, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	applier := kubernetes.NewApplier(logger, r.Client)
-	if err := applier.Apply(ctx, kubernetes.NewManifestReader(data)); err != nil {
-		return err
+	applier := kubernetes.NewApplier(logger, r.Client, kubernetes.WithContinueOnError())
+	conflicts, err := applier.ServerSideApply(ctx, objs)
+
+	var applyErrs kubernetes.ApplyErrors
+	if err != nil && !errors.As(err, &applyErrs) {
+		return nil, nil, err
 	}
 
-	objs, err := decodeObjects(data)
-	if err != nil {
-		return err
+	var manifestConflicts []v1alpha1.ManifestConflict
+	for _, c := range conflicts {
+		manifestConflicts = append(manifestConflicts, v1alpha1.ManifestConflict{
+			ManifestObject: toManifestObject(c.Object),
+			Fields:         c.Fields,
+		})
 	}
-	var manifestObjs []v1alpha1.ManifestObject
-	for _, o := range objs {
-		manifestObjs = append(manifestObjs, v1alpha1.ManifestObject{
-			Group:     o.GroupVersionKind().Group,
-			Version:   o.GroupVersionKind().Version,
-			Kind:      o.GetKind(),
-			Name:      o.GetName(),
-			Namespace: o.GetNamespace(),
+
+	var failures []v1alpha1.ManifestObjectFailure
+	for _, e := range applyErrs {
+		failures = append(failures, v1alpha1.ManifestObjectFailure{
+			ManifestObject: toManifestObject(e.Object),
+			Message:        e.Err.Error(),
 		})
 	}
 
-	// TODO: https://github.com/mirantiscontainers/blueprint-operator/pull/17#discussion_r1408570381
-	// Update the CRD
-
-	crd := &v1alpha1.Manifest{}
-	if err = r.Client.Get(ctx, manifestNamespacedName, crd); err != nil {
-		logger.Error(err, "failed to get manifest resource %s/%s", manifestNamespacedName.Namespace, manifestNamespacedName.Namespace)
-		return fmt.Errorf("failed to get manifest resource %s/%s: %w", manifestNamespacedName.Namespace, manifestNamespacedName.Namespace, err)
-	}
-	// Update the CRD
-	updatedCRD := v1alpha1.Manifest{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            crd.Name,
-			Namespace:       crd.Namespace,
-			ResourceVersion: crd.ResourceVersion,
-		},
-		Spec: v1alpha1.ManifestSpec{
-			Url:           crd.Spec.Url,
-			Checksum:      crd.Spec.Checksum,
-			NewChecksum:   crd.Spec.NewChecksum,
-			FailurePolicy: crd.Spec.FailurePolicy,
-			Timeout:       crd.Spec.Timeout,
-			Objects:       manifestObjs,
-		},
-	}
-
-	if err = r.Update(ctx, &updatedCRD); err != nil {
-		logger.Error(err, "failed to update manifest crd with objectList during create")
-		return err
+	return manifestConflicts, failures, nil
+}
+
+// failuresError returns an error listing the objects that failed to be applied, if any
+func failuresError(failures []v1alpha1.ManifestObjectFailure) error {
+	if len(failures) == 0 {
+		return nil
 	}
 
-	return nil
+	msgs := make([]string, 0, len(failures))
+	for _, f := range failures {
+		msgs = append(msgs, fmt.Sprintf("%s %s: %s", f.Kind, types.NamespacedName{Namespace: f.Namespace, Name: f.Name}, f.Message))
+	}
+	return fmt.Errorf("failed to apply %d objects: %s", len(failures), strings.Join(msgs, "; "))
+}
+
+func toManifestObject(o *unstructured.Unstructured) v1alpha1.ManifestObject {
+	return v1alpha1.ManifestObject{
+		Group:     o.GroupVersionKind().Group,
+		Version:   o.GroupVersionKind().Version,
+		Kind:      o.GetKind(),
+		Name:      o.GetName(),
+		Namespace: o.GetNamespace(),
+	}
 }
 
 func (r *ManifestReconciler) DeleteManifestObjects(ctx context.Context, objectList []v1alpha1.ManifestObject) error {
@@ -446,110 +486,38 @@ func (r *ManifestReconciler) DeleteManifestObjects(ctx context.Context, objectLi
 	return nil
 }
 
-// UpdateManifestObjects reads the manifest from a url and then create or update the new/existing objects in the cluster
-func (r *ManifestReconciler) UpdateManifestObjects(req ctrl.Request, ctx context.Context, existing *v1alpha1.Manifest) error {
+// findAndDeleteObsoleteObjects deletes the objects of the old inventory that are not part of the new inventory
+// The new inventory is only built from a successfully rendered manifest, so an empty one means that every
+// object was removed from the manifest and all the objects of the old inventory are deleted.
+func (r *ManifestReconciler) findAndDeleteObsoleteObjects(ctx context.Context, oldObjects []v1alpha1.ManifestObject, newObjects []v1alpha1.ManifestObject) {
 	logger := log.FromContext(ctx)
 
-	// Create kustomize file, generate kustomize build output and update the objects.
-	bodyBytes, err := kustomize.Render(logger, existing.Spec.Url, existing.Spec.Values)
-
-	if err != nil {
-		logger.Error(err, "failed to fetch manifest file content for url: %s", existing.Spec.Url)
-		return err
-	}
-
-	applier := kubernetes.NewApplier(logger, r.Client)
-
-	if err = applier.Apply(ctx, kubernetes.NewManifestReader(bodyBytes)); err != nil {
-		return err
-	}
-	// Get the list of old objects
-	oldObjects := existing.Spec.Objects
-
-	objs, err := decodeObjects(bodyBytes)
-	if err != nil {
-		return err
-	}
-	var newManifestObjs []v1alpha1.ManifestObject
-	for _, o := range objs {
-		newManifestObjs = append(newManifestObjs, v1alpha1.ManifestObject{
-			Group:     o.GroupVersionKind().Group,
-			Version:   o.GroupVersionKind().Version,
-			Kind:      o.GetKind(),
-			Name:      o.GetName(),
-			Namespace: o.GetNamespace(),
-		})
+	if len(oldObjects) == 0 {
+		return
 	}
 
-	// Update the CRD
-	key := types.NamespacedName{
-		Namespace: req.Namespace,
-		Name:      req.Name,
+	inventory := make(map[string]bool, len(newObjects))
+	for _, n := range newObjects {
+		inventory[inventoryKey(n)] = true
 	}
 
-	crd := &v1alpha1.Manifest{}
-	err = r.Client.Get(ctx, key, crd)
-	if err != nil {
-		logger.Error(err, "failed to get manifest object")
-		return err
+	var obsolete []v1alpha1.ManifestObject
+	for _, old := range oldObjects {
+		if !inventory[inventoryKey(old)] {
+			logger.Info("obsolete object found", "Name", old.Name, "Kind", old.Kind)
+			obsolete = append(obsolete, old)
+		}
 	}
 
-	// @todo (Ranyodh): The CRD should also add finalizer (or do a Patch() update), otherwise, the finalizer will be removed
-	updatedCRD := v1alpha1.Manifest{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            crd.Name,
-			Namespace:       crd.Namespace,
-			ResourceVersion: crd.ResourceVersion,
-		},
-		Spec: v1alpha1.ManifestSpec{
-			Url:           crd.Spec.Url,
-			Checksum:      crd.Spec.NewChecksum,
-			NewChecksum:   crd.Spec.NewChecksum,
-			FailurePolicy: crd.Spec.FailurePolicy,
-			Timeout:       crd.Spec.Timeout,
-			Objects:       newManifestObjs,
-		},
-	}
-
-	if err := r.Update(ctx, &updatedCRD); err != nil {
-		logger.Error(err, "failed to update manifest crd with objectList during update operation")
-		return err
+	if err := r.DeleteManifestObjects(ctx, obsolete); err != nil {
+		logger.Error(err, "failed to delete obsolete objects")
 	}
-
-	// Find the intersection of the new manifest based
-	// objects and old manifest based objects and delete the extra.
-	r.findAndDeleteObsoleteObjects(req, ctx, oldObjects, newManifestObjs)
-
-	return nil
 }
 
-// TODO: https://github.com/mirantiscontainers/blueprint-operator/pull/17#discussion_r1408571732
-func (r *ManifestReconciler) findAndDeleteObsoleteObjects(req ctrl.Request, ctx context.Context, oldObjects []v1alpha1.ManifestObject, newObjects []v1alpha1.ManifestObject) {
-	logger := log.FromContext(ctx)
-
-	var obsolete []v1alpha1.ManifestObject
-
-	if len(oldObjects) > 0 && len(newObjects) > 0 {
-		for _, old := range oldObjects {
-			found := false
-			for _, n := range newObjects {
-				if reflect.DeepEqual(old, n) {
-					found = true
-					break
-				}
-			}
-
-			if found == false {
-				logger.Info("obsolete object found", "Name", old.Name, "Kind", old.Kind)
-				obsolete = append(obsolete, old)
-			}
-
-		}
-
-		if err := r.DeleteManifestObjects(ctx, obsolete); err != nil {
-			logger.Error(err, "failed to delete obsolete objects")
-		}
-	}
+// inventoryKey returns the key of a manifest object in the inventory of a manifest
+// The version is not part of the key, so an object is not pruned when its manifest moves to another API version.
+func inventoryKey(obj v1alpha1.ManifestObject) string {
+	return fmt.Sprintf("%s/%s/%s/%s", obj.Group, obj.Kind, obj.Namespace, obj.Name)
 }
 
 func (r *ManifestReconciler) updateManifestStatus(ctx context.Context, logger logr.Logger, namespacedName types.NamespacedName, objects []v1alpha1.ManifestObject) error {
@@ -558,12 +526,32 @@ func (r *ManifestReconciler) updateManifestStatus(ctx context.Context, logger lo
 	if err != nil {
 		return err
 	}
-	err = r.updateStatus(ctx, logger, namespacedName, manifestStatus.StatusType, manifestStatus.Reason, manifestStatus.Message)
-	if err != nil {
+
+	manifest := &v1alpha1.Manifest{}
+	if err = r.Get(ctx, namespacedName, manifest); err != nil {
+		logger.Error(err, "Failed to get manifest to update status")
 		return err
 	}
 
-	return nil
+	if manifest.Status.Type == manifestStatus.StatusType && manifest.Status.Reason == manifestStatus.Reason &&
+		manifest.Status.Message == manifestStatus.Message && equality.Semantic.DeepEqual(manifest.Status.Health, manifestStatus.Health) {
+		// avoid infinite reconciliation loops
+		logger.Info("No updates to status needed")
+		return nil
+	}
+
+	logger.Info("Update status for manifest", "Name", manifest.Name, "Type", manifestStatus.StatusType, "Reason", manifestStatus.Reason)
+
+	patch := client.MergeFrom(manifest.DeepCopy())
+	if manifest.Status.Type != manifestStatus.StatusType || manifest.Status.Reason != manifestStatus.Reason {
+		manifest.Status.LastTransitionTime = metav1.Now()
+	}
+	manifest.Status.Type = manifestStatus.StatusType
+	manifest.Status.Reason = manifestStatus.Reason
+	manifest.Status.Message = manifestStatus.Message
+	manifest.Status.Health = manifestStatus.Health
+
+	return r.Status().Patch(ctx, manifest, patch)
 }
 
 // updateStatus queries for a fresh Manifest with the provided namespacedName.
@@ -578,8 +566,8 @@ func (r *ManifestReconciler) updateStatus(ctx context.Context, logger logr.Logge
 		return err
 	}
 
-	nilStatus := v1alpha1.ManifestStatus{}
-	if manifest.Status != nilStatus && manifest.Status.Type == typeToApply && manifest.Status.Reason == reasonToApply {
+	nilStatus := v1alpha1.Status{}
+	if manifest.Status.Status != nilStatus && manifest.Status.Type == typeToApply && manifest.Status.Reason == reasonToApply {
 		// avoid infinite reconciliation loops
 		logger.Info("No updates to status needed")
 		return nil
@@ -597,21 +585,3 @@ func (r *ManifestReconciler) updateStatus(ctx context.Context, logger logr.Logge
 
 	return r.Status().Patch(ctx, manifest, patch)
 }
-
-func decodeObjects(data []byte) ([]unstructured.Unstructured, error) {
-	var objs []unstructured.Unstructured
-	decoder := yaml.NewYAMLToJSONDecoder(bytes.NewReader(data))
-
-	var o unstructured.Unstructured
-	for {
-		if err := decoder.Decode(&o); err != nil {
-			if err != io.EOF {
-				return objs, fmt.Errorf("error decoding yaml manifest file: %s", err)
-			}
-			break
-		}
-		objs = append(objs, o)
-
-	}
-	return objs, nil
-}
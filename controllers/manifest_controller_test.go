@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+var _ = Describe("Manifest pruning", func() {
+	var first, second *corev1.ConfigMap
+
+	BeforeEach(func() {
+		first = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "ns"}}
+		second = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ns"}}
+	})
+
+	inventory := func(objs ...*corev1.ConfigMap) []v1alpha1.ManifestObject {
+		var objects []v1alpha1.ManifestObject
+		for _, o := range objs {
+			objects = append(objects, v1alpha1.ManifestObject{Version: "v1", Kind: "ConfigMap", Namespace: o.Namespace, Name: o.Name})
+		}
+		return objects
+	}
+
+	It("deletes the objects that were removed from the manifest", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithObjects(first, second).Build()
+		r := &ManifestReconciler{Client: fakeClient}
+
+		r.findAndDeleteObsoleteObjects(ctx, inventory(first, second), inventory(first))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(first), &corev1.ConfigMap{})).To(Succeed())
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(second), &corev1.ConfigMap{}))).To(BeTrue())
+	})
+
+	It("deletes every object when the manifest is empty", func(ctx context.Context) {
+		fakeClient := fake.NewClientBuilder().WithObjects(first, second).Build()
+		r := &ManifestReconciler{Client: fakeClient}
+
+		r.findAndDeleteObsoleteObjects(ctx, inventory(first, second), nil)
+
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(first), &corev1.ConfigMap{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(second), &corev1.ConfigMap{}))).To(BeTrue())
+	})
+})
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+const (
+	// manifestObjectIndex indexes manifests by the objects in their inventory, keyed by manifestObjectKey
+	manifestObjectIndex = "manifestobjectindex"
+)
+
+// manifestWatcher registers watches on the kinds of the objects in the manifest inventories
+// Watches are registered lazily, the first time a kind appears in an inventory, since the kinds a manifest
+// contains are only known once it has been rendered. Only the metadata of the objects is cached, which is
+// enough to be notified of any change to the objects.
+type manifestWatcher struct {
+	controller controller.Controller
+	cache      cache.Cache
+	// findManifests returns the requests for the manifests containing an object of the provided kind
+	findManifests func(ctx context.Context, gvk schema.GroupVersionKind, obj *metav1.PartialObjectMetadata) []reconcile.Request
+
+	mu      sync.Mutex
+	watched map[schema.GroupVersionKind]bool
+}
+
+// watch registers a watch for each kind of the provided objects that is not watched yet
+func (w *manifestWatcher) watch(logger logr.Logger, objects []v1alpha1.ManifestObject) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, obj := range objects {
+		gvk := schema.GroupVersionKind{Group: obj.Group, Version: obj.Version, Kind: obj.Kind}
+		if w.watched[gvk] {
+			continue
+		}
+
+		logger.Info("Watching manifest object kind", "GroupVersionKind", gvk.String())
+		metadata := &metav1.PartialObjectMetadata{}
+		metadata.SetGroupVersionKind(gvk)
+		h := handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj *metav1.PartialObjectMetadata) []reconcile.Request {
+			return w.findManifests(ctx, gvk, obj)
+		})
+		src := source.Kind(w.cache, metadata, h, predicate.TypedResourceVersionChangedPredicate[*metav1.PartialObjectMetadata]{})
+		if err := w.controller.Watch(src); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", gvk, err)
+		}
+		w.watched[gvk] = true
+	}
+
+	return nil
+}
+
+// manifestObjectKey returns the key of a manifest object in the manifestObjectIndex
+func manifestObjectKey(gvk schema.GroupVersionKind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", gvk.Group, gvk.Version, gvk.Kind, namespace, name)
+}
+
+// indexManifestObjects returns the manifestObjectIndex keys of the objects in the inventory of a manifest
+func indexManifestObjects(rawObj client.Object) []string {
+	manifest := rawObj.(*v1alpha1.Manifest)
+	if len(manifest.Status.Objects) == 0 {
+		return nil
+	}
+
+	indexes := make([]string, 0, len(manifest.Status.Objects))
+	for _, obj := range manifest.Status.Objects {
+		gvk := schema.GroupVersionKind{Group: obj.Group, Version: obj.Version, Kind: obj.Kind}
+		indexes = append(indexes, manifestObjectKey(gvk, obj.Namespace, obj.Name))
+	}
+	return indexes
+}
+
+// findAssociatedManifests finds the manifests whose inventory contains a particular object of the provided kind
+func (r *ManifestReconciler) findAssociatedManifests(ctx context.Context, gvk schema.GroupVersionKind, obj *metav1.PartialObjectMetadata) []reconcile.Request {
+	manifests := &v1alpha1.ManifestList{}
+	key := manifestObjectKey(gvk, obj.GetNamespace(), obj.GetName())
+	if err := r.List(ctx, manifests, client.MatchingFields{manifestObjectIndex: key}); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(manifests.Items))
+	for i, item := range manifests.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+// fakeController records the sources it is asked to watch
+type fakeController struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (c *fakeController) Watch(src source.Source) error {
+	c.sources = append(c.sources, src)
+	return nil
+}
+
+var _ = Describe("Manifest watches", func() {
+	var (
+		deployment  = v1alpha1.ManifestObject{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "a-b", Name: "c"}
+		statefulset = v1alpha1.ManifestObject{Group: "apps", Version: "v1", Kind: "StatefulSet", Namespace: "a", Name: "b-c"}
+		job         = v1alpha1.ManifestObject{Group: "batch", Version: "v1", Kind: "Job", Namespace: "a-b", Name: "c"}
+	)
+
+	Context("Index", func() {
+		It("Should index manifests by the kind, namespace and name of their objects", func() {
+			manifest := &v1alpha1.Manifest{Status: v1alpha1.ManifestStatus{Objects: []v1alpha1.ManifestObject{deployment, statefulset}}}
+			Expect(indexManifestObjects(manifest)).To(Equal([]string{
+				"apps/v1/Deployment/a-b/c",
+				"apps/v1/StatefulSet/a/b-c",
+			}))
+		})
+
+		It("Should not index manifests without objects", func() {
+			Expect(indexManifestObjects(&v1alpha1.Manifest{})).To(BeEmpty())
+		})
+	})
+
+	Context("findAssociatedManifests", func() {
+		It("Should only find the manifests containing the object", func() {
+			first := &v1alpha1.Manifest{
+				ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "blueprint-system"},
+				Status:     v1alpha1.ManifestStatus{Objects: []v1alpha1.ManifestObject{deployment}},
+			}
+			second := &v1alpha1.Manifest{
+				ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "blueprint-system"},
+				Status:     v1alpha1.ManifestStatus{Objects: []v1alpha1.ManifestObject{statefulset, job}},
+			}
+			r := &ManifestReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme.Scheme).
+					WithObjects(first, second).
+					WithIndex(&v1alpha1.Manifest{}, manifestObjectIndex, indexManifestObjects).
+					Build(),
+			}
+
+			obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "a-b", Name: "c"}}
+			requests := r.findAssociatedManifests(context.TODO(), schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, obj)
+			Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "blueprint-system", Name: "second"}}}))
+
+			obj = &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "b-c"}}
+			requests = r.findAssociatedManifests(context.TODO(), schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, obj)
+			Expect(requests).To(BeEmpty())
+		})
+	})
+
+	Context("Watcher", func() {
+		It("Should watch each kind once", func() {
+			c := &fakeController{}
+			w := &manifestWatcher{controller: c, watched: map[schema.GroupVersionKind]bool{}}
+
+			Expect(w.watch(logr.Discard(), []v1alpha1.ManifestObject{deployment, statefulset})).To(Succeed())
+			Expect(w.watch(logr.Discard(), []v1alpha1.ManifestObject{deployment, job})).To(Succeed())
+			Expect(c.sources).To(HaveLen(3))
+		})
+	})
+})
@@ -14,7 +14,7 @@ var (
 		// for those that are taking a long time.
 		Buckets: []float64{1, 2, 3, 4, 5, 7, 10, 12, 15, 18, 20, 25, 30, 60, 120, 180, 300},
 	},
-		// Possible operations - "install", "uninstall"
+		// Possible operations - "install", "uninstall", "upgrade", "repair"
 		// Possible status - "pass", "fail"
 		[]string{"name", "operation", "status"})
 	// addOnHistVec is a histogram vector metric to observe various add ons installed by Blueprint Operator.
@@ -44,6 +44,10 @@ const (
 	metricsOperationInstall = "install"
 	// metricsOperationInstall represents an uninstall operation by Blueprint Operator.
 	metricsOperationUninstall = "uninstall"
+	// metricsOperationUpgrade represents an in-place upgrade operation by Blueprint Operator.
+	metricsOperationUpgrade = "upgrade"
+	// metricsOperationRepair represents the re-apply of a component whose objects drifted, by Blueprint Operator.
+	metricsOperationRepair = "repair"
 	// metricStatusSuccess represents a reconcile operation by Blueprint Operator that succeeds.
 	metricStatusSuccess = "success"
 	// metricStatusFailure represents a reconcile operation by Blueprint Operator that fails.
@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/utils"
 )
@@ -22,7 +23,9 @@ func generateName(obj client.Object) string {
 	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
 }
 
-func listInstalledObjects(ctx context.Context, logger logr.Logger, apiClient client.Client,
+// listInstalledObjects returns the objects listed by the lister that are owned by the blueprint
+// Objects created by a previous version of the operator, which are not owned by any blueprint, are adopted.
+func listInstalledObjects(ctx context.Context, logger logr.Logger, apiClient client.Client, owner *v1alpha1.Blueprint,
 	lister ItemsLister) (map[string]client.Object, error) {
 
 	items, err := lister(ctx, apiClient)
@@ -31,15 +34,31 @@ func listInstalledObjects(ctx context.Context, logger logr.Logger, apiClient cli
 	}
 
 	installedItems := make(map[string]client.Object)
+	var unowned []client.Object
 	for _, item := range items {
 		if item.GetLabels()["app.kubernetes.io/part-of"] == "blueprint-operator" {
 			logger.V(4).Info("skipping BOP object", "Name", item.GetName(), "Namespace", item.GetNamespace())
 			continue
 		}
+		if _, ok := ownerOf(item); !ok {
+			unowned = append(unowned, item)
+			continue
+		}
+		if !isOwnedBy(item, owner) {
+			continue
+		}
 
 		installedItems[generateName(item)] = item
 	}
 
+	adopted, err := adoptLegacyObjects(ctx, logger, apiClient, owner, unowned)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range adopted {
+		installedItems[generateName(item)] = item
+	}
+
 	logger.V(4).Info("installed items", "names", installedItems)
 
 	return installedItems, nil
@@ -80,6 +99,10 @@ func createOrUpdateObject(ctx context.Context, logger logr.Logger, apiClient cli
 	objectExists := err == nil
 
 	if objectExists {
+		if err = checkOwner(existing, desired); err != nil {
+			return err
+		}
+
 		logger.Info("Object already exists. Updating", "Name", existing.GetName(), "Namespace", existing.GetNamespace())
 
 		desired.SetResourceVersion(existing.GetResourceVersion())
@@ -102,15 +125,18 @@ func createOrUpdateObject(ctx context.Context, logger logr.Logger, apiClient cli
 	return nil
 }
 
-func reconcileObjects(ctx context.Context, logger logr.Logger, apiClient client.Client,
+// reconcileObjects creates or updates the objects as owned by the blueprint and deletes the objects it owns
+// that are no longer desired
+func reconcileObjects(ctx context.Context, logger logr.Logger, apiClient client.Client, owner *v1alpha1.Blueprint,
 	objects []client.Object, lister ItemsLister) error {
 
-	objectsToUninstall, err := listInstalledObjects(ctx, logger, apiClient, lister)
+	objectsToUninstall, err := listInstalledObjects(ctx, logger, apiClient, owner, lister)
 	if err != nil {
 		return err
 	}
 
 	for _, o := range objects {
+		setOwner(o, owner)
 		if o.GetNamespace() != "" {
 			err = utils.CreateNamespaceIfNotExist(apiClient, ctx, logger, o.GetNamespace())
 			if err != nil {
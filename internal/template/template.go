@@ -3,12 +3,31 @@ package template
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io/fs"
 	"path"
 )
 
+// funcs are the functions available to the templates
+// toJson renders a value as JSON, which is also valid YAML flow style, so that structured values such as
+// tolerations or resources can be rendered on a single line.
+var funcs = template.FuncMap{
+	"toJson": toJSON,
+}
+
+// toJSON returns the JSON encoding of the value as is, since escaping it would break the JSON
+// The JSON encoder already escapes the HTML characters of strings. The other values of the templates are still
+// escaped as before.
+func toJSON(v interface{}) (template.HTML, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(b), nil
+}
+
 func executeTemplate(t *template.Template, cfg interface{}) (*bytes.Buffer, error) {
 	buf := &bytes.Buffer{}
 	err := t.Execute(buf, cfg)
@@ -22,7 +41,7 @@ func executeTemplate(t *template.Template, cfg interface{}) (*bytes.Buffer, erro
 // ParseTemplate receives an input template and a config struct and returns the
 // executed template.
 func ParseTemplate(sourceTmpl string, cfg interface{}) (*bytes.Buffer, error) {
-	tmpl, err := template.New("dummyTemplate").Parse(sourceTmpl)
+	tmpl, err := template.New("dummyTemplate").Funcs(funcs).Parse(sourceTmpl)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse template: %s", err)
 	}
@@ -33,7 +52,7 @@ func ParseTemplate(sourceTmpl string, cfg interface{}) (*bytes.Buffer, error) {
 // ParseFSTemplate parses template from the supplied file system at the specified path
 // using the supplied config struct and returns the executed template.
 func ParseFSTemplate(sourceTmpl fs.FS, pathname string, cfg interface{}) (*bytes.Buffer, error) {
-	tmpl, err := template.New(path.Base(pathname)).ParseFS(sourceTmpl, pathname)
+	tmpl, err := template.New(path.Base(pathname)).Funcs(funcs).ParseFS(sourceTmpl, pathname)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse template: %s", err)
 	}
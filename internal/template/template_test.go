@@ -0,0 +1,87 @@
+package template
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+type testConfig struct {
+	Image    string
+	CABundle string
+	Name     string
+}
+
+// renderWithoutFuncs renders the template as it was rendered before the template functions were added
+func renderWithoutFuncs(t *testing.T, source string, cfg interface{}) string {
+	tmpl, err := htmltemplate.New("reference").Parse(source)
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	require.NoError(t, tmpl.Execute(buf, cfg))
+	return buf.String()
+}
+
+func TestParseTemplateOutputUnchanged(t *testing.T) {
+	cfg := testConfig{
+		Image:    "registry.example.com/operator:v1.0.0",
+		CABundle: "LS0tLS1CRUdJTi+/AB==",
+		Name:     `app "web" <a&b>`,
+	}
+
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "image",
+			source: "image: {{.Image}}",
+		},
+		{
+			name:   "base64 value",
+			source: "caBundle: {{ .CABundle }}",
+		},
+		{
+			name:   "quoted value",
+			source: `name: "{{ .Name }}"`,
+		},
+		{
+			name:   "conditional",
+			source: "{{- if .Name }}\nname: {{ .Name }}\n{{- end }}",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseTemplate(tc.source, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, renderWithoutFuncs(t, tc.source, cfg), got.String())
+
+			fsys := fstest.MapFS{"manifests/test.yaml": {Data: []byte(tc.source)}}
+			got, err = ParseFSTemplate(fsys, "manifests/test.yaml", cfg)
+			require.NoError(t, err)
+			assert.Equal(t, renderWithoutFuncs(t, tc.source, cfg), got.String())
+		})
+	}
+}
+
+func TestParseTemplateToJSON(t *testing.T) {
+	tolerations := []corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: `infra&"db"<1>`, Effect: corev1.TaintEffectNoSchedule},
+	}
+
+	got, err := ParseTemplate("tolerations: {{ toJson .Tolerations }}", struct{ Tolerations []corev1.Toleration }{tolerations})
+	require.NoError(t, err)
+	assert.Equal(t, `tolerations: [{"key":"dedicated","operator":"Equal","value":"infra\u0026\"db\"\u003c1\u003e","effect":"NoSchedule"}]`, got.String())
+
+	parsed := struct {
+		Tolerations []corev1.Toleration `json:"tolerations"`
+	}{}
+	require.NoError(t, yaml.Unmarshal(got.Bytes(), &parsed))
+	assert.Equal(t, tolerations, parsed.Tolerations)
+}
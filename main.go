@@ -160,6 +160,7 @@ func main() {
 			Scheme:        mgr.GetScheme(),
 			SetupLogger:   setupLog,
 			ImageRegistry: imageRegistry,
+			Recorder:      mgr.GetEventRecorderFor("installation controller"),
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "Installation")
 			os.Exit(1)
@@ -185,7 +186,7 @@ func main() {
 }
 
 func printImages(imageRegistry string) {
-	for _, c := range controllers.AllComponents(nil, log.Log, imageRegistry) {
+	for _, c := range controllers.AllComponents(nil, log.Log, imageRegistry, v1alpha1.InstallationSpec{}) {
 		for _, image := range c.Images() {
 			// the println is used instead of logging for easier parsing
 			fmt.Println(image)
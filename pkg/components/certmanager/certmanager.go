@@ -8,8 +8,10 @@ import (
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/internal/template"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
@@ -18,6 +20,9 @@ import (
 )
 
 const (
+	// ComponentName is the name of the cert manager component
+	ComponentName = "cert-manager"
+
 	namespaceCertManager  = "cert-manager"
 	deploymentCAInjector  = "cert-manager-cainjector"
 	deploymentCertManager = "cert-manager"
@@ -30,11 +35,19 @@ const (
 	webhookImage    = "jetstack/cert-manager-webhook"
 )
 
+// requiredCRDs are the CRDs an external cert manager installation must provide
+var requiredCRDs = []string{
+	"certificates.cert-manager.io",
+	"issuers.cert-manager.io",
+	"clusterissuers.cert-manager.io",
+}
+
 // certManager is a component that manages cert manager in the cluster.
 type certManager struct {
 	client        client.Client
 	logger        logr.Logger
 	imageRegistry string
+	spec          *v1alpha1.ComponentSpec
 }
 
 // imageConfig holds the images for the cert manager components
@@ -44,35 +57,49 @@ type imageConfig struct {
 	WebhookImage    string
 }
 
-func newImageConfig(registry string) imageConfig {
-	if registry == "" {
-		registry = consts.MirantisImageRegistry
-	}
+// manifestConfig holds the values the cert manager manifest is rendered with
+type manifestConfig struct {
+	imageConfig
+	Deployment components.DeploymentConfig
+}
 
+func newImageConfig(registry string, spec *v1alpha1.ComponentSpec) imageConfig {
 	return imageConfig{
-		CAInjectorImage: fmt.Sprintf("%s/%s:%s", registry, caInjectorImage, consts.CertManagerCAInjectorImageTag),
-		ControllerImage: fmt.Sprintf("%s/%s:%s", registry, controllerImage, consts.CertManagerControllerImageTag),
-		WebhookImage:    fmt.Sprintf("%s/%s:%s", registry, webhookImage, consts.CertManagerWebhookImageTag),
+		CAInjectorImage: components.Image(registry, spec, caInjectorImage, consts.CertManagerCAInjectorImageTag),
+		ControllerImage: components.Image(registry, spec, controllerImage, consts.CertManagerControllerImageTag),
+		WebhookImage:    components.Image(registry, spec, webhookImage, consts.CertManagerWebhookImageTag),
 	}
 }
 
-// NewCertManagerComponent creates a new instance of the cert manager component.
-func NewCertManagerComponent(client client.Client, logger logr.Logger, imageRegistry string) components.Component {
+// NewCertManagerComponent creates a new instance of the cert manager component configured with the spec from
+// the Installation.
+func NewCertManagerComponent(client client.Client, logger logr.Logger, imageRegistry string, spec *v1alpha1.ComponentSpec) components.Component {
 	return &certManager{
 		client:        client,
 		logger:        logger,
 		imageRegistry: imageRegistry,
+		spec:          spec,
 	}
 }
 
 // Name returns the name of the component.
 func (c *certManager) Name() string {
-	return "cert-manager"
+	return ComponentName
+}
+
+// Enabled returns whether cert manager is enabled in the Installation.
+func (c *certManager) Enabled() bool {
+	return c.spec.IsEnabled()
 }
 
 // Images returns the images used by cert manager.
+// An external cert manager installation doesn't use any image of the operator.
 func (c *certManager) Images() []string {
-	images := newImageConfig(c.imageRegistry)
+	if c.spec.IsExternal() {
+		return []string{}
+	}
+
+	images := newImageConfig(c.imageRegistry, c.spec)
 
 	return []string{
 		images.CAInjectorImage,
@@ -82,9 +109,12 @@ func (c *certManager) Images() []string {
 }
 
 func (c *certManager) renderManifest() ([]byte, error) {
-	images := newImageConfig(c.imageRegistry)
+	cfg := manifestConfig{
+		imageConfig: newImageConfig(c.imageRegistry, c.spec),
+		Deployment:  components.NewDeploymentConfig(c.spec),
+	}
 
-	manifest, err := template.ParseTemplate(certManagerTemplate, images)
+	manifest, err := template.ParseTemplate(certManagerTemplate, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse cert-manager manifest template: %w", err)
 	}
@@ -92,18 +122,75 @@ func (c *certManager) renderManifest() ([]byte, error) {
 	return manifest.Bytes(), nil
 }
 
-// Install installs cert manager in the cluster.
-func (c *certManager) Install(ctx context.Context) error {
-	c.logger.Info("Installing cert manager")
+// DesiredVersion returns the version of cert manager bundled with the operator.
+func (c *certManager) DesiredVersion(_ context.Context) (string, error) {
+	return components.Version(c.Images()), nil
+}
+
+// Dependencies returns the components cert manager depends on, which are none.
+func (c *certManager) Dependencies() []string {
+	return nil
+}
+
+// Deployments returns the deployments of cert manager.
+// The deployments of an external cert manager installation are not managed by the operator.
+func (c *certManager) Deployments(ctx context.Context) ([]client.ObjectKey, error) {
+	if external, err := c.isExternal(ctx); err != nil || external {
+		return nil, err
+	}
 
+	var deployments []client.ObjectKey
+	for _, name := range []string{deploymentCAInjector, deploymentCertManager, deploymentWebhook} {
+		deployments = append(deployments, client.ObjectKey{Namespace: consts.NamespaceBlueprintSystem, Name: name})
+	}
+	return deployments, nil
+}
+
+// Objects returns the objects of the cert manager manifest.
+// The objects of an external cert manager installation are not managed by the operator.
+func (c *certManager) Objects(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	if external, err := c.isExternal(ctx); err != nil || external {
+		return nil, err
+	}
+
+	certManagerManifest, err := c.renderManifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to render cert-manager manifest: %w", err)
+	}
+	return kubernetes.NewManifestReader(certManagerManifest).ReadManifest()
+}
+
+// InstalledVersion returns the version of cert manager from the images of its deployments.
+// The version of an external cert manager installation is not managed by the operator.
+func (c *certManager) InstalledVersion(ctx context.Context) (string, error) {
+	deployments, err := c.Deployments(ctx)
+	if err != nil || len(deployments) == 0 {
+		return "", err
+	}
+	return components.InstalledVersion(ctx, c.client, deployments)
+}
+
+// Install installs cert manager in the cluster, or upgrades it in place.
+// It doesn't wait for cert manager to be ready, which is checked by Ready.
+func (c *certManager) Install(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	external, err := c.isExternal(ctx)
+	if err != nil {
+		return err
+	}
+	if external {
+		return c.checkExternal(ctx)
+	}
+
+	c.logger.Info("Installing cert manager")
+
 	if err := utils.CreateNamespaceIfNotExist(c.client, ctx, c.logger, consts.NamespaceBlueprintSystem); err != nil {
 		return err
 	}
 
-	applier := kubernetes.NewApplier(c.logger, c.client)
+	applier := kubernetes.NewApplier(c.logger, c.client, kubernetes.WithLabels(components.Labels(c.Name())))
 
 	certManagerManifest, err := c.renderManifest()
 	if err != nil {
@@ -130,22 +217,6 @@ func (c *certManager) Install(ctx context.Context) error {
 		return err
 	}
 
-	// Wait for all the deployments to be ready
-	c.logger.Info("waiting for ca injector deployment to be ready")
-	if err := utils.WaitForDeploymentReady(ctx, c.logger, c.client, deploymentCAInjector, consts.NamespaceBlueprintSystem); err != nil {
-		return err
-	}
-
-	c.logger.Info("waiting for cert manager deployment to be ready")
-	if err := utils.WaitForDeploymentReady(ctx, c.logger, c.client, deploymentCertManager, consts.NamespaceBlueprintSystem); err != nil {
-		return err
-	}
-
-	c.logger.Info("waiting for webhook deployment to be ready")
-	if err := utils.WaitForDeploymentReady(ctx, c.logger, c.client, deploymentWebhook, consts.NamespaceBlueprintSystem); err != nil {
-		return err
-	}
-
 	c.logger.Info("finished installing cert manager")
 
 	return nil
@@ -153,6 +224,11 @@ func (c *certManager) Install(ctx context.Context) error {
 
 // Uninstall uninstalls cert manager from the cluster.
 func (c *certManager) Uninstall(ctx context.Context) error {
+	if c.spec.IsExternal() {
+		c.logger.Info("Not uninstalling external cert manager")
+		return nil
+	}
+
 	c.logger.Info("uninstalling cert manager")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -181,7 +257,12 @@ func (c *certManager) Uninstall(ctx context.Context) error {
 
 // CheckExists checks if cert manager is already installed in the cluster.
 // This shall check both BOP specific as well as external installations.
+// For a cert manager declared as external, it checks that the cert manager CRDs exist.
 func (c *certManager) CheckExists(ctx context.Context) (bool, error) {
+	if c.spec.IsExternal() {
+		return components.CheckCRDsExist(ctx, c.client, requiredCRDs)
+	}
+
 	// First, we check if an external cert manager instance already exists in the cluster.
 	exists, err := checkIfExternalCertManagerExists(ctx, c.client)
 	if err != nil {
@@ -206,6 +287,38 @@ func (c *certManager) CheckExists(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// Ready checks if the deployments of cert manager are ready, so that certificates can be issued.
+// An external cert manager installation is ready once the cert manager CRDs exist.
+func (c *certManager) Ready(ctx context.Context) (bool, error) {
+	external, err := c.isExternal(ctx)
+	if err != nil {
+		return false, err
+	}
+	if external {
+		return components.CheckCRDsExist(ctx, c.client, requiredCRDs)
+	}
+
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return false, err
+	}
+	return components.DeploymentsReady(ctx, c.client, deployments)
+}
+
+// isExternal checks if cert manager is declared as external, or if an external cert manager instance was
+// detected in the cluster.
+func (c *certManager) isExternal(ctx context.Context) (bool, error) {
+	if c.spec.IsExternal() {
+		return true, nil
+	}
+
+	exists, err := checkIfExternalCertManagerExists(ctx, c.client)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if an external cert-manager installation already exists in the cluster: %w", err)
+	}
+	return exists, nil
+}
+
 // checkIfExternalCertManagerExists checks if an external cert manager instance already exists.
 func checkIfExternalCertManagerExists(ctx context.Context, runtimeClient client.Client) (bool, error) {
 	key := client.ObjectKey{
@@ -221,3 +334,16 @@ func checkIfExternalCertManagerExists(ctx context.Context, runtimeClient client.
 
 	return true, nil
 }
+
+// checkExternal checks that the external cert manager installation is present
+func (c *certManager) checkExternal(ctx context.Context) error {
+	exists, err := components.CheckCRDsExist(ctx, c.client, requiredCRDs)
+	if err != nil {
+		return fmt.Errorf("failed to check external cert-manager installation: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("cert-manager is declared as external but its CRDs are not installed: %v", requiredCRDs)
+	}
+	c.logger.Info("Using external cert manager installation")
+	return nil
+}
@@ -0,0 +1,116 @@
+package certmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/kubernetes"
+)
+
+// renderDeployments renders the cert manager manifest and returns its deployments
+func renderDeployments(t *testing.T, spec *v1alpha1.ComponentSpec) map[string]*appsv1.Deployment {
+	c := NewCertManagerComponent(nil, logr.Discard(), "registry.example.com", spec).(*certManager)
+	rendered, err := c.renderManifest()
+	require.NoError(t, err)
+
+	objs, err := kubernetes.NewManifestReader(rendered).ReadManifest()
+	require.NoError(t, err)
+
+	deployments := map[string]*appsv1.Deployment{}
+	for _, o := range objs {
+		if o.GetKind() != "Deployment" {
+			continue
+		}
+		d := &appsv1.Deployment{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object, d))
+		deployments[d.Name] = d
+	}
+	require.Len(t, deployments, 3)
+	return deployments
+}
+
+func TestRenderManifestDefaults(t *testing.T) {
+	for name, d := range renderDeployments(t, nil) {
+		assert.Equal(t, int32(1), *d.Spec.Replicas, name)
+		assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, d.Spec.Template.Spec.NodeSelector, name)
+		assert.Equal(t, "node-role.kubernetes.io/master", d.Spec.Template.Spec.Tolerations[0].Key, name)
+		assert.Empty(t, d.Spec.Template.Spec.Containers[0].Resources, name)
+		assert.Contains(t, d.Spec.Template.Spec.Containers[0].Image, "registry.example.com/jetstack/", name)
+	}
+}
+
+func TestRenderManifestOverrides(t *testing.T) {
+	resources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+	}
+	tolerations := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "infra", Effect: corev1.TaintEffectNoSchedule}}
+	spec := &v1alpha1.ComponentSpec{
+		Image: &v1alpha1.ImageSpec{
+			Registry: "mirror.example.com/jetstack-mirror",
+			Tags:     map[string]string{caInjectorImage: "v1.9.2", controllerImage: "v1.9.2", webhookImage: "v1.9.2"},
+		},
+		Replicas:     ptr.To[int32](2),
+		Resources:    resources,
+		Tolerations:  tolerations,
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+	}
+
+	for name, d := range renderDeployments(t, spec) {
+		podSpec := d.Spec.Template.Spec
+		assert.Equal(t, int32(2), *d.Spec.Replicas, name)
+		assert.Equal(t, spec.NodeSelector, podSpec.NodeSelector, name)
+		assert.Equal(t, tolerations, podSpec.Tolerations, name)
+		assert.True(t, resources.Limits.Memory().Equal(*podSpec.Containers[0].Resources.Limits.Memory()), name)
+		assert.Regexp(t, `^mirror\.example\.com/jetstack-mirror/jetstack/cert-manager-[a-z]+:v1\.9\.2$`, podSpec.Containers[0].Image, name)
+	}
+}
+
+func TestImagesOfExternalCertManager(t *testing.T) {
+	c := NewCertManagerComponent(nil, logr.Discard(), "", &v1alpha1.ComponentSpec{External: true})
+	assert.Empty(t, c.Images())
+
+	c = NewCertManagerComponent(nil, logr.Discard(), "", nil)
+	assert.Len(t, c.Images(), 3)
+}
+
+// TestObjectsNoDrift checks that the rendered objects don't drift once they went through the API types, so that
+// the objects are not repaired on every reconciliation. Custom resources and their definitions are not known to the
+// fake client, and are skipped.
+func TestObjectsNoDrift(t *testing.T) {
+	spec := &v1alpha1.ComponentSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.5")},
+		},
+	}
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+	objs, err := NewCertManagerComponent(c, logr.Discard(), "registry.example.com", spec).Objects(ctx)
+	require.NoError(t, err)
+
+	var applied []*unstructured.Unstructured
+	for _, o := range objs {
+		if o.GetKind() == "CustomResourceDefinition" || strings.HasSuffix(o.GroupVersionKind().Group, "cert-manager.io") {
+			continue
+		}
+		require.NoError(t, c.Create(ctx, o.DeepCopy()), o.GetName())
+		applied = append(applied, o)
+	}
+
+	drifted, err := components.Drift(ctx, c, applied)
+	require.NoError(t, err)
+	assert.Empty(t, drifted, "expected the cert manager objects not to drift")
+}
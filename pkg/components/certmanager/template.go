@@ -5149,7 +5149,7 @@ metadata:
     app.kubernetes.io/component: "cainjector"
     app.kubernetes.io/version: "v1.9.1"
 spec:
-  replicas: 1
+  replicas: {{ .Deployment.Replicas }}
   selector:
     matchLabels:
       app.kubernetes.io/name: cainjector
@@ -5167,13 +5167,20 @@ spec:
       serviceAccountName: cert-manager-cainjector
       securityContext:
         runAsNonRoot: true
+      {{- with .Deployment.Tolerations }}
+      tolerations: {{ toJson . }}
+      {{- else }}
       tolerations:
       - key: node-role.kubernetes.io/master
         effect: NoSchedule
+      {{- end }}
       containers:
         - name: cert-manager
           image: {{ .CAInjectorImage }}
           imagePullPolicy: IfNotPresent
+          {{- with .Deployment.Resources }}
+          resources: {{ toJson . }}
+          {{- end }}
           args:
           - --v=2
           - --leader-election-namespace=kube-system
@@ -5184,8 +5191,12 @@ spec:
                 fieldPath: metadata.namespace
           securityContext:
             allowPrivilegeEscalation: false
+      {{- with .Deployment.NodeSelector }}
+      nodeSelector: {{ toJson . }}
+      {{- else }}
       nodeSelector:
         kubernetes.io/os: linux
+      {{- end }}
 ---
 # Source: cert-manager/templates/deployment.yaml
 apiVersion: apps/v1
@@ -5200,7 +5211,7 @@ metadata:
     app.kubernetes.io/component: "controller"
     app.kubernetes.io/version: "v1.9.1"
 spec:
-  replicas: 1
+  replicas: {{ .Deployment.Replicas }}
   selector:
     matchLabels:
       app.kubernetes.io/name: cert-manager
@@ -5222,13 +5233,20 @@ spec:
       serviceAccountName: cert-manager
       securityContext:
         runAsNonRoot: true
+      {{- with .Deployment.Tolerations }}
+      tolerations: {{ toJson . }}
+      {{- else }}
       tolerations:
       - key: node-role.kubernetes.io/master
         effect: NoSchedule
+      {{- end }}
       containers:
         - name: cert-manager
           image: {{ .ControllerImage }}
           imagePullPolicy: IfNotPresent
+          {{- with .Deployment.Resources }}
+          resources: {{ toJson . }}
+          {{- end }}
           args:
           - --v=2
           - --cluster-resource-namespace=$(POD_NAMESPACE)
@@ -5244,8 +5262,12 @@ spec:
             valueFrom:
               fieldRef:
                 fieldPath: metadata.namespace
+      {{- with .Deployment.NodeSelector }}
+      nodeSelector: {{ toJson . }}
+      {{- else }}
       nodeSelector:
         kubernetes.io/os: linux
+      {{- end }}
 ---
 # Source: cert-manager/templates/webhook-deployment.yaml
 apiVersion: apps/v1
@@ -5260,7 +5282,7 @@ metadata:
     app.kubernetes.io/component: "webhook"
     app.kubernetes.io/version: "v1.9.1"
 spec:
-  replicas: 1
+  replicas: {{ .Deployment.Replicas }}
   selector:
     matchLabels:
       app.kubernetes.io/name: webhook
@@ -5278,13 +5300,20 @@ spec:
       serviceAccountName: cert-manager-webhook
       securityContext:
         runAsNonRoot: true
+      {{- with .Deployment.Tolerations }}
+      tolerations: {{ toJson . }}
+      {{- else }}
       tolerations:
       - key: node-role.kubernetes.io/master
         effect: NoSchedule
+      {{- end }}
       containers:
         - name: cert-manager
           image: {{ .WebhookImage }}
           imagePullPolicy: IfNotPresent
+          {{- with .Deployment.Resources }}
+          resources: {{ toJson . }}
+          {{- end }}
           args:
           - --v=2
           - --secure-port=10250
@@ -5322,8 +5351,12 @@ spec:
             valueFrom:
               fieldRef:
                 fieldPath: metadata.namespace
+      {{- with .Deployment.NodeSelector }}
+      nodeSelector: {{ toJson . }}
+      {{- else }}
       nodeSelector:
         kubernetes.io/os: linux
+      {{- end }}
 ---
 # Source: cert-manager/templates/webhook-mutating-webhook.yaml
 apiVersion: admissionregistration.k8s.io/v1
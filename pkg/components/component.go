@@ -1,16 +1,43 @@
 package components
 
-import "context"
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
 
 type Component interface {
 
 	// Name returns the name of the component
 	Name() string
 
+	// Enabled returns whether the component is enabled in the Installation
+	Enabled() bool
+
 	// Images returns the images used by the component
 	Images() []string
 
-	// Install installs the component
+	// Dependencies returns the names of the components that must be ready before the component is installed
+	Dependencies() []string
+
+	// Deployments returns the deployments run by the component, which its health and version are read from
+	// It returns an empty list if the deployments of the component are not managed by the operator.
+	Deployments(ctx context.Context) ([]client.ObjectKey, error)
+
+	// Objects returns the objects rendered by the component, which are re-applied when they drift in the cluster
+	// It returns an empty list if the objects of the component are not managed by the operator.
+	Objects(ctx context.Context) ([]*unstructured.Unstructured, error)
+
+	// DesiredVersion returns the version of the component bundled with the operator
+	// It returns an empty string if the version of the component is not managed by the operator.
+	DesiredVersion(ctx context.Context) (string, error)
+
+	// InstalledVersion returns the version of the component installed in the cluster
+	// It returns an empty string if the component is not installed or its version is not managed by the operator.
+	InstalledVersion(ctx context.Context) (string, error)
+
+	// Install installs the component, or upgrades it in place along with its CRDs if it is already installed
 	Install(ctx context.Context) error
 
 	// Uninstall uninstalls the component
@@ -18,4 +45,7 @@ type Component interface {
 
 	// CheckExists checks if the component exists
 	CheckExists(ctx context.Context) (bool, error)
+
+	// Ready checks if the component is ready to be used by the components depending on it
+	Ready(ctx context.Context) (bool, error)
 }
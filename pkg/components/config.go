@@ -0,0 +1,53 @@
+package components
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+// DeploymentConfig holds the values the deployments of a component are rendered with
+// Unset values are left to the defaults of the component templates.
+type DeploymentConfig struct {
+	Replicas     int32
+	Resources    *corev1.ResourceRequirements
+	Tolerations  []corev1.Toleration
+	NodeSelector map[string]string
+}
+
+// NewDeploymentConfig returns the values to render the deployments of a component with from its Installation spec
+func NewDeploymentConfig(spec *v1alpha1.ComponentSpec) DeploymentConfig {
+	cfg := DeploymentConfig{Replicas: 1}
+	if spec == nil {
+		return cfg
+	}
+
+	if spec.Replicas != nil {
+		cfg.Replicas = *spec.Replicas
+	}
+	cfg.Resources = spec.Resources
+	cfg.Tolerations = spec.Tolerations
+	cfg.NodeSelector = spec.NodeSelector
+	return cfg
+}
+
+// Image returns the reference of an image of a component
+// The registry of the images and the tag of each image can be overridden in the Installation spec of the component.
+func Image(registry string, spec *v1alpha1.ComponentSpec, name, tag string) string {
+	if spec != nil && spec.Image != nil {
+		if spec.Image.Registry != "" {
+			registry = spec.Image.Registry
+		}
+		if t := spec.Image.Tags[name]; t != "" {
+			tag = t
+		}
+	}
+	if registry == "" {
+		registry = consts.MirantisImageRegistry
+	}
+
+	return fmt.Sprintf("%s/%s:%s", registry, name, tag)
+}
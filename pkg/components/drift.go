@@ -0,0 +1,149 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+// Labels returns the labels set on the objects of a component, which the objects of the component are watched by
+func Labels(name string) map[string]string {
+	return map[string]string{consts.ComponentLabel: name}
+}
+
+// Drift returns the objects of a component that were deleted or modified in the cluster
+// An object is modified if any of the fields set in the desired object differs in the cluster. Fields that are
+// only set in the cluster, such as defaults, the status or fields set by other controllers, are ignored.
+func Drift(ctx context.Context, c client.Client, desired []*unstructured.Unstructured) ([]string, error) {
+	var drifted []string
+	for _, obj := range desired {
+		name := fmt.Sprintf("%s %s", obj.GetKind(), client.ObjectKeyFromObject(obj))
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+			if apierrors.IsNotFound(err) {
+				drifted = append(drifted, fmt.Sprintf("%s (deleted)", name))
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s: %w", name, err)
+		}
+
+		desiredContent := obj.DeepCopy().UnstructuredContent()
+		delete(desiredContent, "status")
+		if !isSubset(desiredContent, live.UnstructuredContent()) {
+			drifted = append(drifted, fmt.Sprintf("%s (modified)", name))
+		}
+	}
+	return drifted, nil
+}
+
+// isSubset returns true if every value set in desired is set to the same value in live
+// Empty and zero values in desired are considered unset, as the API server omits them. Numbers are compared by
+// value, and quantities, such as resource requests, are compared by the amount they represent.
+func isSubset(desired, live interface{}) bool {
+	if isEmpty(desired) {
+		return true
+	}
+
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range d {
+			if !isSubset(v, l[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	case string, int64, float64:
+		if reflect.DeepEqual(desired, live) {
+			return true
+		}
+		dn, dok := toFloat(desired)
+		ln, lok := toFloat(live)
+		if dok && lok {
+			return dn == ln
+		}
+		return isSameQuantity(desired, live)
+	default:
+		return reflect.DeepEqual(desired, live)
+	}
+}
+
+// isEmpty returns true if the value is nil, an empty map or list, or a zero value
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+// toFloat returns the value of a number of unstructured content
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// isSameQuantity returns true if both values are quantities of the same amount
+// Quantities can be written as numbers in manifests, while they are always strings in the cluster.
+func isSameQuantity(desired, live interface{}) bool {
+	dq, ok := toQuantity(desired)
+	if !ok {
+		return false
+	}
+	lq, ok := toQuantity(live)
+	return ok && dq.Cmp(lq) == 0
+}
+
+// toQuantity parses a string or a number of unstructured content as a quantity
+func toQuantity(v interface{}) (resource.Quantity, bool) {
+	s, ok := v.(string)
+	if n, isNumber := toFloat(v); isNumber {
+		s, ok = strconv.FormatFloat(n, 'f', -1, 64), true
+	}
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(s)
+	return q, err == nil
+}
@@ -0,0 +1,93 @@
+package components
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+const desiredDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: helm-controller
+  namespace: flux-system
+  creationTimestamp: null
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/mirantiscontainers/fluxcd/helm-controller:v0.37.4
+        ports:
+        - containerPort: 8080
+        resources:
+          requests:
+            cpu: 0.5
+            memory: 64Mi
+      tolerations: []
+`
+
+func toUnstructured(t *testing.T, manifest string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal([]byte(manifest), &obj.Object))
+	return obj
+}
+
+func TestIsSubset(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired interface{}
+		live    interface{}
+		subset  bool
+	}{
+		{name: "defaults added in the cluster", desired: map[string]interface{}{"a": "b"}, live: map[string]interface{}{"a": "b", "c": "d"}, subset: true},
+		{name: "modified value", desired: map[string]interface{}{"a": "b"}, live: map[string]interface{}{"a": "c"}},
+		{name: "removed value", desired: map[string]interface{}{"a": "b"}, live: map[string]interface{}{}},
+		{name: "empty desired value", desired: map[string]interface{}{"a": []interface{}{}}, live: map[string]interface{}{}, subset: true},
+		{name: "zero desired values", desired: map[string]interface{}{"a": "", "b": false, "c": int64(0)}, live: map[string]interface{}{}, subset: true},
+		{name: "added list item", desired: []interface{}{"a"}, live: []interface{}{"a", "b"}},
+		{name: "numbers", desired: int64(1), live: float64(1), subset: true},
+		{name: "quantities", desired: "0.5", live: "500m", subset: true},
+		{name: "quantities written as numbers", desired: 0.5, live: "500m", subset: true},
+		{name: "different quantities", desired: "1Gi", live: "1G"},
+		{name: "strings that are not quantities", desired: "1", live: "a"},
+		{name: "booleans", desired: true, live: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.subset, isSubset(tc.desired, tc.live))
+		})
+	}
+}
+
+func TestDrift(t *testing.T) {
+	ctx := context.TODO()
+	desired := toUnstructured(t, desiredDeployment)
+	c := fake.NewClientBuilder().Build()
+
+	drifted, err := Drift(ctx, c, []*unstructured.Unstructured{desired})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Deployment flux-system/helm-controller (deleted)"}, drifted)
+
+	require.NoError(t, c.Create(ctx, desired.DeepCopy()))
+	drifted, err = Drift(ctx, c, []*unstructured.Unstructured{desired})
+	require.NoError(t, err)
+	assert.Empty(t, drifted)
+
+	live := desired.DeepCopy()
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(desired), live))
+	require.NoError(t, unstructured.SetNestedField(live.Object, int64(0), "spec", "replicas"))
+	require.NoError(t, c.Update(ctx, live))
+	drifted, err = Drift(ctx, c, []*unstructured.Unstructured{desired})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Deployment flux-system/helm-controller (modified)"}, drifted)
+}
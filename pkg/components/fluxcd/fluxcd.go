@@ -9,8 +9,10 @@ import (
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/kubernetes"
@@ -27,8 +29,11 @@ var (
 )
 
 const (
-	fluxCDNamespace    = "flux-system"
-	helmControllerName = "helm-controller"
+	fluxCDNamespace            = "flux-system"
+	helmControllerName         = "helm-controller"
+	kustomizeControllerName    = "kustomize-controller"
+	notificationControllerName = "notification-controller"
+	sourceControllerName       = "source-controller"
 
 	// images
 
@@ -38,11 +43,19 @@ const (
 	sourceControllerImage       = "fluxcd/source-controller"
 )
 
+// requiredCRDs are the CRDs an external FluxCD installation must provide
+var requiredCRDs = []string{
+	"helmreleases.helm.toolkit.fluxcd.io",
+	"helmrepositories.source.toolkit.fluxcd.io",
+	"helmcharts.source.toolkit.fluxcd.io",
+}
+
 type fluxcdComponent struct {
 	applier       *kubernetes.Applier
 	client        client.Client
 	logger        logr.Logger
 	imageRegistry string
+	spec          *v1alpha1.ComponentSpec
 }
 
 type imageConfig struct {
@@ -52,27 +65,31 @@ type imageConfig struct {
 	SourceControllerImage       string
 }
 
-func newImageConfig(registry string) imageConfig {
-	if registry == "" {
-		registry = consts.MirantisImageRegistry
-	}
+// manifestConfig holds the values the FluxCD manifests are rendered with
+type manifestConfig struct {
+	imageConfig
+	Deployment components.DeploymentConfig
+}
 
+func newImageConfig(registry string, spec *v1alpha1.ComponentSpec) imageConfig {
 	return imageConfig{
-		HelmControllerImage:         fmt.Sprintf("%s/%s:%s", registry, helmControllerImage, consts.FluxCDHelmControllerImageTag),
-		KustomizeControllerImage:    fmt.Sprintf("%s/%s:%s", registry, kustomizeControllerImage, consts.FluxCDKustomizeControllerImageTag),
-		NotificationControllerImage: fmt.Sprintf("%s/%s:%s", registry, notificationControllerImage, consts.FluxCDNotificationControllerImageTag),
-		SourceControllerImage:       fmt.Sprintf("%s/%s:%s", registry, sourceControllerImage, consts.FluxCDSourceControllerImageTag),
+		HelmControllerImage:         components.Image(registry, spec, helmControllerImage, consts.FluxCDHelmControllerImageTag),
+		KustomizeControllerImage:    components.Image(registry, spec, kustomizeControllerImage, consts.FluxCDKustomizeControllerImageTag),
+		NotificationControllerImage: components.Image(registry, spec, notificationControllerImage, consts.FluxCDNotificationControllerImageTag),
+		SourceControllerImage:       components.Image(registry, spec, sourceControllerImage, consts.FluxCDSourceControllerImageTag),
 	}
 }
 
-// NewFluxCDComponent creates a new instance of the fluxcd component.
-func NewFluxCDComponent(client client.Client, logger logr.Logger, imageRegistry string) components.Component {
-	return &fluxcdComponent{
-		applier:       kubernetes.NewApplier(logger, client),
+// NewFluxCDComponent creates a new instance of the fluxcd component configured with the spec from the Installation.
+func NewFluxCDComponent(client client.Client, logger logr.Logger, imageRegistry string, spec *v1alpha1.ComponentSpec) components.Component {
+	c := &fluxcdComponent{
 		client:        client,
 		logger:        logger,
 		imageRegistry: imageRegistry,
+		spec:          spec,
 	}
+	c.applier = kubernetes.NewApplier(logger, client, kubernetes.WithLabels(components.Labels(c.Name())))
+	return c
 }
 
 // Name returns the name of the component
@@ -80,9 +97,19 @@ func (c *fluxcdComponent) Name() string {
 	return "fluxcd"
 }
 
+// Enabled returns whether fluxcd is enabled in the Installation
+func (c *fluxcdComponent) Enabled() bool {
+	return c.spec.IsEnabled()
+}
+
 // Images returns the images used by fluxcd
+// An external fluxcd installation doesn't use any image of the operator.
 func (c *fluxcdComponent) Images() []string {
-	images := newImageConfig(c.imageRegistry)
+	if c.spec.IsExternal() {
+		return []string{}
+	}
+
+	images := newImageConfig(c.imageRegistry, c.spec)
 
 	return []string{
 		images.HelmControllerImage,
@@ -92,12 +119,74 @@ func (c *fluxcdComponent) Images() []string {
 	}
 }
 
+// DesiredVersion returns the version of fluxcd bundled with the operator
+func (c *fluxcdComponent) DesiredVersion(_ context.Context) (string, error) {
+	return components.Version(c.Images()), nil
+}
+
+// Dependencies returns the components fluxcd depends on, which are none
+func (c *fluxcdComponent) Dependencies() []string {
+	return nil
+}
+
+// Deployments returns the deployments of the fluxcd controllers
+// The deployments of an external fluxcd installation are not managed by the operator.
+func (c *fluxcdComponent) Deployments(_ context.Context) ([]client.ObjectKey, error) {
+	if c.spec.IsExternal() {
+		return nil, nil
+	}
+
+	var deployments []client.ObjectKey
+	for _, name := range []string{helmControllerName, kustomizeControllerName, notificationControllerName, sourceControllerName} {
+		deployments = append(deployments, client.ObjectKey{Namespace: fluxCDNamespace, Name: name})
+	}
+	return deployments, nil
+}
+
+// Objects returns the CRDs and the objects of the fluxcd manifests
+// The objects of an external fluxcd installation are not managed by the operator.
+func (c *fluxcdComponent) Objects(_ context.Context) ([]*unstructured.Unstructured, error) {
+	if c.spec.IsExternal() {
+		return nil, nil
+	}
+
+	crds, err := manifest.Read(crdsFiles, "crds")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FluxCD CRDs: %w", err)
+	}
+	resources, err := manifest.ReadTemplate(manifestsFiles, "manifests", c.manifestConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FluxCD manifests: %w", err)
+	}
+	return append(crds, resources...), nil
+}
+
+// InstalledVersion returns the version of fluxcd from the images of its controllers
+// The version of an external fluxcd installation is not managed by the operator.
+func (c *fluxcdComponent) InstalledVersion(ctx context.Context) (string, error) {
+	if c.spec.IsExternal() {
+		return "", nil
+	}
+
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return "", err
+	}
+	return components.InstalledVersion(ctx, c.client, deployments)
+}
+
 // Install installs the fluxcd component
+// CRDs are applied before the controllers, so that an upgrade doesn't run controllers against outdated CRDs.
 func (c *fluxcdComponent) Install(ctx context.Context) error {
-	c.logger.Info("Installing fluxcd")
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	if c.spec.IsExternal() {
+		return c.checkExternal(ctx)
+	}
+
+	c.logger.Info("Installing fluxcd")
+
 	// create namespace if not exists
 	if err := utils.CreateNamespaceIfNotExist(c.client, ctx, c.logger, fluxCDNamespace); err != nil {
 		return fmt.Errorf("failed to create namespace flux-system: %w", err)
@@ -117,11 +206,16 @@ func (c *fluxcdComponent) Install(ctx context.Context) error {
 
 // Uninstall uninstalls the fluxcd component
 func (c *fluxcdComponent) Uninstall(ctx context.Context) error {
+	if c.spec.IsExternal() {
+		c.logger.Info("Not uninstalling external fluxcd")
+		return nil
+	}
+
 	c.logger.Info("Uninstalling fluxcd")
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	resources, err := manifest.Read(manifestsFiles, "manifests")
+	resources, err := manifest.ReadTemplate(manifestsFiles, "manifests", c.manifestConfig())
 	if err != nil {
 		return fmt.Errorf("failed to read FluxCD manifests: %w", err)
 	}
@@ -147,7 +241,12 @@ func (c *fluxcdComponent) Uninstall(ctx context.Context) error {
 }
 
 // CheckExists checks if the fluxcd component exists
+// For an external fluxcd installation, it checks that the FluxCD CRDs used by the operator exist.
 func (c *fluxcdComponent) CheckExists(ctx context.Context) (bool, error) {
+	if c.spec.IsExternal() {
+		return components.CheckCRDsExist(ctx, c.client, requiredCRDs)
+	}
+
 	key := client.ObjectKey{Namespace: fluxCDNamespace, Name: helmControllerName}
 
 	if err := c.client.Get(ctx, key, &v1.Deployment{}); err != nil {
@@ -160,6 +259,20 @@ func (c *fluxcdComponent) CheckExists(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// Ready checks if the fluxcd controllers are ready
+// An external fluxcd installation is ready once the FluxCD CRDs used by the operator exist.
+func (c *fluxcdComponent) Ready(ctx context.Context) (bool, error) {
+	if c.spec.IsExternal() {
+		return components.CheckCRDsExist(ctx, c.client, requiredCRDs)
+	}
+
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return false, err
+	}
+	return components.DeploymentsReady(ctx, c.client, deployments)
+}
+
 func (c *fluxcdComponent) installCRDs(ctx context.Context) error {
 	c.logger.V(1).Info("Reading FluxCD CRDs")
 	resources, err := manifest.Read(crdsFiles, "crds")
@@ -184,10 +297,29 @@ func (c *fluxcdComponent) installCRDs(ctx context.Context) error {
 	return nil
 }
 
-func (c *fluxcdComponent) installFluxCD(ctx context.Context) error {
-	images := newImageConfig(c.imageRegistry)
+// checkExternal checks that the external fluxcd installation is present
+func (c *fluxcdComponent) checkExternal(ctx context.Context) error {
+	exists, err := components.CheckCRDsExist(ctx, c.client, requiredCRDs)
+	if err != nil {
+		return fmt.Errorf("failed to check external fluxcd installation: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("fluxcd is declared as external but its CRDs are not installed: %v", requiredCRDs)
+	}
+	c.logger.Info("Using external fluxcd installation")
+	return nil
+}
 
-	resources, err := manifest.ReadTemplate(manifestsFiles, "manifests", images)
+// manifestConfig returns the values to render the FluxCD manifests with
+func (c *fluxcdComponent) manifestConfig() manifestConfig {
+	return manifestConfig{
+		imageConfig: newImageConfig(c.imageRegistry, c.spec),
+		Deployment:  components.NewDeploymentConfig(c.spec),
+	}
+}
+
+func (c *fluxcdComponent) installFluxCD(ctx context.Context) error {
+	resources, err := manifest.ReadTemplate(manifestsFiles, "manifests", c.manifestConfig())
 	if err != nil {
 		return fmt.Errorf("failed to read FluxCD manifests: %w", err)
 	}
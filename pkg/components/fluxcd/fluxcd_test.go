@@ -0,0 +1,108 @@
+package fluxcd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/manifest"
+)
+
+// renderDeployments renders the FluxCD manifests and returns their deployments
+func renderDeployments(t *testing.T, spec *v1alpha1.ComponentSpec) map[string]*appsv1.Deployment {
+	c := NewFluxCDComponent(nil, logr.Discard(), "registry.example.com", spec).(*fluxcdComponent)
+	objs, err := manifest.ReadTemplate(manifestsFiles, "manifests", c.manifestConfig())
+	require.NoError(t, err)
+
+	deployments := map[string]*appsv1.Deployment{}
+	for _, o := range objs {
+		if o.GetKind() != "Deployment" {
+			continue
+		}
+		d := &appsv1.Deployment{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object, d))
+		deployments[d.Name] = d
+	}
+	require.Len(t, deployments, 4)
+	return deployments
+}
+
+func TestRenderManifestsDefaults(t *testing.T) {
+	for name, d := range renderDeployments(t, nil) {
+		podSpec := d.Spec.Template.Spec
+		assert.Equal(t, int32(1), *d.Spec.Replicas, name)
+		assert.Empty(t, podSpec.NodeSelector, name)
+		assert.Equal(t, "node-role.kubernetes.io/master", podSpec.Tolerations[0].Key, name)
+		assert.Equal(t, "1Gi", podSpec.Containers[0].Resources.Limits.Memory().String(), name)
+		assert.Contains(t, podSpec.Containers[0].Image, "registry.example.com/fluxcd/", name)
+	}
+}
+
+func TestRenderManifestsOverrides(t *testing.T) {
+	resources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+	}
+	tolerations := []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	spec := &v1alpha1.ComponentSpec{
+		Image:        &v1alpha1.ImageSpec{Tags: map[string]string{helmControllerImage: "v1.0.2"}},
+		Replicas:     ptr.To[int32](3),
+		Resources:    resources,
+		Tolerations:  tolerations,
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+	}
+
+	for name, d := range renderDeployments(t, spec) {
+		podSpec := d.Spec.Template.Spec
+		assert.Equal(t, int32(3), *d.Spec.Replicas, name)
+		assert.Equal(t, spec.NodeSelector, podSpec.NodeSelector, name)
+		assert.Equal(t, tolerations, podSpec.Tolerations, name)
+		assert.Empty(t, podSpec.Containers[0].Resources.Limits, name)
+		assert.Equal(t, "200m", podSpec.Containers[0].Resources.Requests.Cpu().String(), name)
+	}
+
+	// only the tag of the helm controller is overridden, as the controllers have different versions
+	deployments := renderDeployments(t, spec)
+	assert.Equal(t, "registry.example.com/fluxcd/helm-controller:v1.0.2", deployments["helm-controller"].Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "registry.example.com/fluxcd/source-controller:v1.3.0", deployments["source-controller"].Spec.Template.Spec.Containers[0].Image)
+}
+
+// TestObjectsNoDrift checks that the rendered objects don't drift once they went through the API types, so that
+// the objects are not repaired on every reconciliation. Custom resources and their definitions are not known to the
+// fake client, and are skipped.
+func TestObjectsNoDrift(t *testing.T) {
+	spec := &v1alpha1.ComponentSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.5")},
+		},
+	}
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+	objs, err := NewFluxCDComponent(c, logr.Discard(), "registry.example.com", spec).Objects(ctx)
+	require.NoError(t, err)
+
+	var applied []*unstructured.Unstructured
+	for _, o := range objs {
+		if o.GetKind() == "CustomResourceDefinition" || strings.HasSuffix(o.GroupVersionKind().Group, "cert-manager.io") {
+			continue
+		}
+		require.NoError(t, c.Create(ctx, o.DeepCopy()), o.GetName())
+		applied = append(applied, o)
+	}
+
+	drifted, err := components.Drift(ctx, c, applied)
+	require.NoError(t, err)
+	assert.Empty(t, drifted, "expected the FluxCD objects not to drift")
+}
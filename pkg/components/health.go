@@ -0,0 +1,93 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeploymentsHealth is the health of the deployments of a component
+type DeploymentsHealth struct {
+	// NotReady lists the deployments that are missing or don't have all their replicas available yet
+	NotReady []string
+	// Failed lists the deployments that failed to roll out
+	Failed []string
+}
+
+// Ready returns true if all the deployments are ready
+func (h DeploymentsHealth) Ready() bool {
+	return len(h.NotReady) == 0 && len(h.Failed) == 0
+}
+
+// Degraded returns true if any of the deployments failed to roll out
+func (h DeploymentsHealth) Degraded() bool {
+	return len(h.Failed) > 0
+}
+
+// DeploymentsReady returns true if all the deployments exist and are ready
+func DeploymentsReady(ctx context.Context, c client.Client, deployments []client.ObjectKey) (bool, error) {
+	health, err := CheckDeployments(ctx, c, deployments)
+	if err != nil {
+		return false, err
+	}
+	return health.Ready(), nil
+}
+
+// CheckDeployments returns the health of the deployments of a component
+// A deployment is ready once its latest spec has been observed, all its replicas are available and it has the
+// Available condition. It failed if its progress deadline is exceeded or its replicas can't be created.
+func CheckDeployments(ctx context.Context, c client.Client, deployments []client.ObjectKey) (DeploymentsHealth, error) {
+	health := DeploymentsHealth{}
+	for _, key := range deployments {
+		d := &appsv1.Deployment{}
+		if err := c.Get(ctx, key, d); err != nil {
+			if apierrors.IsNotFound(err) {
+				health.NotReady = append(health.NotReady, fmt.Sprintf("%s (not found)", key.Name))
+				continue
+			}
+			return health, fmt.Errorf("failed to get deployment %s: %w", key, err)
+		}
+
+		if msg, failed := deploymentFailure(d); failed {
+			health.Failed = append(health.Failed, fmt.Sprintf("%s (%s)", d.Name, msg))
+			continue
+		}
+
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		if d.Status.ObservedGeneration < d.Generation || d.Status.UpdatedReplicas < replicas ||
+			d.Status.AvailableReplicas < replicas || !hasDeploymentCondition(d, appsv1.DeploymentAvailable) {
+			health.NotReady = append(health.NotReady, fmt.Sprintf("%s (%d/%d available)", d.Name, d.Status.AvailableReplicas, replicas))
+		}
+	}
+	return health, nil
+}
+
+// deploymentFailure returns the message of the condition reporting that the deployment failed to roll out, if any
+func deploymentFailure(d *appsv1.Deployment) (string, bool) {
+	for _, cond := range d.Status.Conditions {
+		switch {
+		case cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse && cond.Reason == "ProgressDeadlineExceeded":
+			return cond.Message, true
+		case cond.Type == appsv1.DeploymentReplicaFailure && cond.Status == corev1.ConditionTrue:
+			return cond.Message, true
+		}
+	}
+	return "", false
+}
+
+// hasDeploymentCondition returns true if the deployment has the condition with a true status
+func hasDeploymentCondition(d *appsv1.Deployment, conditionType appsv1.DeploymentConditionType) bool {
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == conditionType {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -38,3 +38,16 @@ func WaitForCRDs(ctx context.Context, c client.Client, logger logr.Logger, crdNa
 
 	return nil
 }
+
+// CheckCRDsExist checks if all the given CRDs exist in the cluster.
+func CheckCRDsExist(ctx context.Context, c client.Client, crdNames []string) (bool, error) {
+	for _, crdName := range crdNames {
+		if err := c.Get(ctx, client.ObjectKey{Name: crdName}, &apiextensionsv1.CustomResourceDefinition{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	return true, nil
+}
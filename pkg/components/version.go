@@ -0,0 +1,69 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Version returns the version of a component from the tags of its images
+// The version is the tag shared by all the images, or the name and the tag of each image when they differ.
+func Version(images []string) string {
+	tags := map[string]string{}
+	shared := ""
+	for i, image := range images {
+		name, tag := splitImage(image)
+		tags[name] = tag
+		if i == 0 {
+			shared = tag
+		} else if tag != shared {
+			shared = ""
+		}
+	}
+	if shared != "" || len(tags) == 0 {
+		return shared
+	}
+
+	versions := make([]string, 0, len(tags))
+	for name, tag := range tags {
+		versions = append(versions, fmt.Sprintf("%s:%s", name, tag))
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ", ")
+}
+
+// InstalledVersion returns the version of a component from the images of its deployments in the cluster
+// It returns an empty string if any of the deployments doesn't exist.
+func InstalledVersion(ctx context.Context, c client.Client, deployments []client.ObjectKey) (string, error) {
+	var images []string
+	for _, key := range deployments {
+		d := &appsv1.Deployment{}
+		if err := c.Get(ctx, key, d); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to get deployment %s: %w", key, err)
+		}
+		for _, container := range d.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+	}
+	return Version(images), nil
+}
+
+// splitImage returns the name, without the registry, and the tag or digest of an image reference
+func splitImage(image string) (string, string) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return path.Base(image[:i]), image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return path.Base(image[:i]), image[i+1:]
+	}
+	return path.Base(image), "latest"
+}
@@ -0,0 +1,90 @@
+package components
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		images   []string
+		expected string
+	}{
+		{
+			name:     "no images",
+			expected: "",
+		},
+		{
+			name:     "shared tag",
+			images:   []string{"registry.example.com/cert-manager-controller:v1.9.1", "registry.example.com/cert-manager-webhook:v1.9.1"},
+			expected: "v1.9.1",
+		},
+		{
+			name:     "registry with a port",
+			images:   []string{"registry.example.com:5000/blueprint-operator:v1.2.0"},
+			expected: "v1.2.0",
+		},
+		{
+			name:     "no tag",
+			images:   []string{"registry.example.com:5000/blueprint-operator"},
+			expected: "latest",
+		},
+		{
+			name:     "digest",
+			images:   []string{"registry.example.com/blueprint-operator@sha256:abc"},
+			expected: "sha256:abc",
+		},
+		{
+			name:     "different tags",
+			images:   []string{"registry.example.com/source-controller:v1.2.4", "registry.example.com/helm-controller:v0.37.4"},
+			expected: "helm-controller:v0.37.4, source-controller:v1.2.4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Version(tc.images))
+		})
+	}
+}
+
+func TestInstalledVersion(t *testing.T) {
+	deployment := func(name, image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: image}}},
+				},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		deployment("controller", "registry.example.com/controller:v1.0.0"),
+		deployment("webhook", "registry.example.com/webhook:v1.0.0"),
+	).Build()
+	ctx := context.TODO()
+
+	version, err := InstalledVersion(ctx, c, []client.ObjectKey{
+		{Name: "controller", Namespace: "test"},
+		{Name: "webhook", Namespace: "test"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", version)
+
+	version, err = InstalledVersion(ctx, c, []client.ObjectKey{
+		{Name: "controller", Namespace: "test"},
+		{Name: "cainjector", Namespace: "test"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, version, "expected no version when a deployment is missing")
+}
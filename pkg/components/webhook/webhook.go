@@ -8,35 +8,39 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
 	"github.com/mirantiscontainers/blueprint-operator/internal/template"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/components"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/components/certmanager"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/kubernetes"
 	"github.com/mirantiscontainers/blueprint-operator/pkg/utils"
 )
 
 const (
-	serviceWebhook          = "blueprint-operator-webhook-service"
-	webhookServerSecretName = "blueprint-webhook-server-cert"
+	serviceWebhook = "blueprint-operator-webhook-service"
 )
 
 // webhook is a component that manages validation webhooks in the cluster.
 type webhook struct {
 	client client.Client
 	logger logr.Logger
+	spec   *v1alpha1.WebhookSpec
 }
 
 type webhookConfig struct {
 	Image string
 }
 
-// NewWebhookComponent creates a new instance of the webhook component.
-func NewWebhookComponent(client client.Client, logger logr.Logger) components.Component {
+// NewWebhookComponent creates a new instance of the webhook component configured with the spec from the Installation.
+func NewWebhookComponent(client client.Client, logger logr.Logger, spec *v1alpha1.WebhookSpec) components.Component {
 	return &webhook{
 		client: client,
 		logger: logger,
+		spec:   spec,
 	}
 }
 
@@ -51,13 +55,70 @@ func (c *webhook) Name() string {
 	return "webhook"
 }
 
-// Install installs webhooks in the cluster.
+// Enabled returns whether the webhook is enabled in the Installation
+func (c *webhook) Enabled() bool {
+	return c.spec.IsEnabled()
+}
+
+// DesiredVersion returns the version of the operator, as the webhook runs the image of the operator.
+func (c *webhook) DesiredVersion(ctx context.Context) (string, error) {
+	operatorImage, err := utils.GetOperatorImage(ctx, c.client)
+	if err != nil {
+		return "", err
+	}
+	return components.Version([]string{operatorImage}), nil
+}
+
+// Dependencies returns cert manager, which issues the certificate of the webhook server.
+func (c *webhook) Dependencies() []string {
+	return []string{certmanager.ComponentName}
+}
+
+// Deployments returns the deployment serving the webhooks.
+func (c *webhook) Deployments(_ context.Context) ([]client.ObjectKey, error) {
+	return []client.ObjectKey{{Namespace: consts.NamespaceBlueprintSystem, Name: consts.BlueprintOperatorWebhookName}}, nil
+}
+
+// Objects returns the certificate resources and the webhook resources rendered with the image of the operator.
+func (c *webhook) Objects(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	operatorImage, err := utils.GetOperatorImage(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := template.ParseTemplate(webhookTemplate, webhookConfig{Image: operatorImage})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+
+	objs, err := kubernetes.NewManifestReader([]byte(certificateTemplate)).ReadManifest()
+	if err != nil {
+		return nil, err
+	}
+	webhookObjs, err := kubernetes.NewManifestReader(rendered.Bytes()).ReadManifest()
+	if err != nil {
+		return nil, err
+	}
+	return append(objs, webhookObjs...), nil
+}
+
+// InstalledVersion returns the version of the webhook from the image of its deployment.
+func (c *webhook) InstalledVersion(ctx context.Context) (string, error) {
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return "", err
+	}
+	return components.InstalledVersion(ctx, c.client, deployments)
+}
+
+// Install installs webhooks in the cluster, or upgrades them in place.
+// The webhook server starts once cert manager has issued its certificate, which is checked by Ready.
 func (c *webhook) Install(ctx context.Context) error {
 	c.logger.Info("Installing validation webhooks")
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	applier := kubernetes.NewApplier(c.logger, c.client)
+	applier := kubernetes.NewApplier(c.logger, c.client, kubernetes.WithLabels(components.Labels(c.Name())))
 
 	operatorImage, err := utils.GetOperatorImage(ctx, c.client)
 	if err != nil {
@@ -71,11 +132,6 @@ func (c *webhook) Install(ctx context.Context) error {
 		return err
 	}
 
-	// Wait for the secret to be created before creating the webhook resources
-	if err := utils.WaitForSecret(ctx, c.client, webhookServerSecretName, consts.NamespaceBlueprintSystem); err != nil {
-		return err
-	}
-
 	cfg := webhookConfig{
 		Image: operatorImage,
 	}
@@ -143,3 +199,12 @@ func (c *webhook) CheckExists(ctx context.Context) (bool, error) {
 
 	return true, nil
 }
+
+// Ready checks if the deployment serving the webhooks is ready.
+func (c *webhook) Ready(ctx context.Context) (bool, error) {
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return false, err
+	}
+	return components.DeploymentsReady(ctx, c.client, deployments)
+}
@@ -22,6 +22,15 @@ const (
 	// ManagedByValue is the label value used to identify resources managed by the blueprint operator
 	ManagedByValue = "blueprint-operator"
 
+	// BlueprintNameLabel is the label used to identify the blueprint that owns an addon or resource
+	BlueprintNameLabel = "blueprint.mirantis.com/blueprint-name"
+
+	// BlueprintNamespaceLabel is the label used to identify the namespace of the blueprint that owns an addon or resource
+	BlueprintNamespaceLabel = "blueprint.mirantis.com/blueprint-namespace"
+
+	// ComponentLabel is the label used to identify the objects of the component installed by the operator they belong to
+	ComponentLabel = "blueprint.mirantis.com/component"
+
 	// MirantisImageRegistry is the default image registry for Mirantis images
 	MirantisImageRegistry = "ghcr.io/mirantiscontainers"
 )
@@ -0,0 +1,261 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+const chartFetchTimeout = 60 * time.Second
+
+// repoAuth holds the credentials and TLS configuration used to access a private chart repository
+type repoAuth struct {
+	username string
+	password string
+
+	// dockerConfig is the content of a kubernetes.io/dockerconfigjson Secret, used instead of the username and
+	// password for OCI registries
+	dockerConfig []byte
+
+	// tlsConfig is used instead of the default TLS configuration when set
+	tlsConfig *tls.Config
+
+	// passCredentials allows the credentials to be sent to a chart URL on a different host than the repository
+	passCredentials bool
+}
+
+// loadChart downloads and loads the chart of an addon from its HTTP(S) or OCI repository
+// The version may be an exact version or a semver range, the latest matching version is used.
+// The auth is optional and only needed for private repositories.
+func loadChart(chartSpec *v1alpha1.ChartInfo, auth *repoAuth) (*chart.Chart, error) {
+	if registry.IsOCI(chartSpec.Repo) {
+		return loadOCIChart(chartSpec, auth)
+	}
+	return loadRepoChart(chartSpec, auth)
+}
+
+// loadRepoChart downloads a chart from a Helm repository, looking up the chart URL in the repository index
+func loadRepoChart(chartSpec *v1alpha1.ChartInfo, auth *repoAuth) (*chart.Chart, error) {
+	// the URL of the repository is set, so that the credentials are only sent to another host if explicitly allowed
+	opts := []getter.Option{
+		getter.WithURL(chartSpec.Repo),
+		getter.WithTimeout(chartFetchTimeout),
+		getter.WithTransport(newTransport(auth)),
+	}
+	if auth != nil {
+		opts = append(opts,
+			getter.WithBasicAuth(auth.username, auth.password),
+			getter.WithPassCredentialsAll(auth.passCredentials),
+		)
+	}
+
+	g, err := getter.NewHTTPGetter(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	indexURL, err := repo.ResolveReferenceURL(chartSpec.Repo, "index.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL %q: %w", chartSpec.Repo, err)
+	}
+	data, err := g.Get(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download repository index: %w", err)
+	}
+
+	index := &repo.IndexFile{}
+	if err = yaml.Unmarshal(data.Bytes(), index); err != nil {
+		return nil, fmt.Errorf("failed to parse repository index: %w", err)
+	}
+	index.SortEntries()
+
+	version, err := index.Get(chartSpec.Name, chartSpec.Version)
+	if err != nil {
+		return nil, fmt.Errorf("chart %s version %s not found in repository %s: %w", chartSpec.Name, chartSpec.Version, chartSpec.Repo, err)
+	}
+	if len(version.URLs) == 0 {
+		return nil, fmt.Errorf("chart %s-%s has no download URLs", chartSpec.Name, version.Version)
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(chartSpec.Repo, version.URLs[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart URL %q: %w", version.URLs[0], err)
+	}
+	if data, err = g.Get(chartURL); err != nil {
+		return nil, fmt.Errorf("failed to download chart %s-%s: %w", chartSpec.Name, version.Version, err)
+	}
+
+	return loader.LoadArchive(data)
+}
+
+// loadOCIChart pulls a chart from an OCI registry, looking up the version in the tags of the chart
+func loadOCIChart(chartSpec *v1alpha1.ChartInfo, auth *repoAuth) (*chart.Chart, error) {
+	ref := strings.TrimPrefix(strings.TrimSuffix(chartSpec.Repo, "/"), fmt.Sprintf("%s://", registry.OCIScheme)) + "/" + chartSpec.Name
+
+	// the registry client reads the credentials from a docker config file, so they are written to a temporary directory
+	dir, err := os.MkdirTemp("", "blueprint-chart-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	registryClient, err := newRegistryClient(dir, ref, chartSpec.Insecure, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := registryClient.Tags(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tags of chart %s: %w", ref, err)
+	}
+	tag, err := registry.GetTagMatchingVersionOrConstraint(tags, chartSpec.Version)
+	if err != nil {
+		return nil, fmt.Errorf("chart %s version %s not found in repository %s: %w", chartSpec.Name, chartSpec.Version, chartSpec.Repo, err)
+	}
+
+	result, err := registryClient.Pull(ref+":"+tag, registry.PullOptWithChart(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download chart %s-%s: %w", chartSpec.Name, tag, err)
+	}
+
+	return loader.LoadArchive(bytes.NewReader(result.Chart.Data))
+}
+
+// newRegistryClient creates a client for the registry of the chart reference
+// The credentials are written to a docker config file in dir.
+func newRegistryClient(dir, ref string, plainHTTP bool, auth *repoAuth) (*registry.Client, error) {
+	data, err := dockerConfig(ref, auth)
+	if err != nil {
+		return nil, err
+	}
+	credentialsFile := filepath.Join(dir, "config.json")
+	if err = os.WriteFile(credentialsFile, data, 0600); err != nil {
+		return nil, err
+	}
+
+	opts := []registry.ClientOption{
+		registry.ClientOptCredentialsFile(credentialsFile),
+		registry.ClientOptHTTPClient(&http.Client{Timeout: chartFetchTimeout, Transport: newTransport(auth)}),
+		registry.ClientOptWriter(io.Discard),
+	}
+	if plainHTTP {
+		opts = append(opts, registry.ClientOptPlainHTTP())
+	}
+	return registry.NewClient(opts...)
+}
+
+// dockerConfig returns the docker config holding the credentials of the registry of the chart reference
+func dockerConfig(ref string, auth *repoAuth) ([]byte, error) {
+	if auth != nil && len(auth.dockerConfig) > 0 {
+		return auth.dockerConfig, nil
+	}
+
+	auths := map[string]interface{}{}
+	if auth != nil && auth.username != "" {
+		host := strings.SplitN(ref, "/", 2)[0]
+		auths[host] = map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte(auth.username + ":" + auth.password))}
+	}
+	return json.Marshal(map[string]interface{}{"auths": auths})
+}
+
+// newTransport returns an HTTP transport that uses the TLS configuration of the repository, if any
+func newTransport(auth *repoAuth) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// as with Helm, the chart archives are already compressed
+	transport.DisableCompression = true
+	if auth != nil && auth.tlsConfig != nil {
+		transport.TLSClientConfig = auth.tlsConfig
+	}
+	return transport
+}
+
+// getRepoAuth builds the credentials and TLS configuration to access the chart repository from the Secrets
+// referenced by the chart. It returns nil if the repository is public.
+func getRepoAuth(ctx context.Context, c client.Client, chartSpec *v1alpha1.ChartInfo) (*repoAuth, error) {
+	if chartSpec.SecretRef == nil && chartSpec.CertSecretRef == nil {
+		return nil, nil
+	}
+
+	auth := &repoAuth{passCredentials: chartSpec.PassCredentials}
+
+	if chartSpec.SecretRef != nil {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: chartSpec.SecretRef.Name}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get repository credentials secret %s: %w", chartSpec.SecretRef.Name, err)
+		}
+		auth.username = string(secret.Data["username"])
+		auth.password = string(secret.Data["password"])
+		if secret.Type == corev1.SecretTypeDockerConfigJson {
+			auth.dockerConfig = secret.Data[corev1.DockerConfigJsonKey]
+		}
+	}
+
+	if chartSpec.CertSecretRef != nil {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: chartSpec.CertSecretRef.Name}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get repository certificate secret %s: %w", chartSpec.CertSecretRef.Name, err)
+		}
+
+		tlsConfig, err := tlsConfigFromSecret(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository certificate secret %s: %w", chartSpec.CertSecretRef.Name, err)
+		}
+		auth.tlsConfig = tlsConfig
+	}
+
+	return auth, nil
+}
+
+// tlsConfigFromSecret builds a TLS configuration from the ca.crt, tls.crt and tls.key keys of a Secret
+func tlsConfigFromSecret(secret *corev1.Secret) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if ca, ok := secret.Data["ca.crt"]; ok {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in ca.crt")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	cert, hasCert := secret.Data["tls.crt"]
+	key, hasKey := secret.Data["tls.key"]
+	if hasCert != hasKey {
+		return nil, fmt.Errorf("tls.crt and tls.key must be provided together")
+	}
+	if hasCert {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
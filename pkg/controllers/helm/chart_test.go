@@ -0,0 +1,306 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+func TestLoadChart(t *testing.T) {
+	archives := map[string][]byte{}
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		archives["/charts/app-"+version+".tgz"] = chartArchive(t, map[string]string{
+			"app/Chart.yaml":            "apiVersion: v2\nname: app\nversion: " + version + "\n",
+			"app/values.yaml":           "replicas: 2\n",
+			"app/templates/svc.yaml":    "apiVersion: v1\nkind: Service\n",
+			"app/crds/crd.yaml":         "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\n",
+			"app/files/config.ini":      "key=value\n",
+			"app/charts/sub/Chart.yaml": "apiVersion: v2\nname: sub\nversion: 0.1.0\n",
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/charts/index.yaml" {
+			_, _ = w.Write([]byte(`apiVersion: v1
+entries:
+  app:
+    - {name: app, version: 1.0.0, urls: [app-1.0.0.tgz]}
+    - {name: app, version: 1.1.0, urls: [app-1.1.0.tgz]}
+    - {name: app, version: 2.0.0, urls: [app-2.0.0.tgz]}
+`))
+			return
+		}
+		if archive, ok := archives[r.URL.Path]; ok {
+			_, _ = w.Write(archive)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		repo            string
+		version         string
+		expectedVersion string
+		expectedError   string
+	}{
+		{name: "exact version", repo: server.URL + "/charts", version: "1.0.0", expectedVersion: "1.0.0"},
+		{name: "version range", repo: server.URL + "/charts", version: "^1.0.0", expectedVersion: "1.1.0"},
+		{name: "version not found", repo: server.URL + "/charts", version: "3.0.0", expectedError: "not found in repository"},
+		{name: "missing index", repo: server.URL + "/other", version: "1.0.0", expectedError: "failed to download repository index"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := loadChart(&v1alpha1.ChartInfo{Name: "app", Repo: tc.repo, Version: tc.version}, nil)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.Metadata.Name != "app" || c.Metadata.Version != tc.expectedVersion || c.Values["replicas"] != float64(2) {
+				t.Errorf("unexpected chart metadata %+v or values %v", c.Metadata, c.Values)
+			}
+			if len(c.Templates) != 1 || c.Templates[0].Name != "templates/svc.yaml" {
+				t.Errorf("expected template templates/svc.yaml, got %v", c.Templates)
+			}
+			if len(c.CRDObjects()) != 1 {
+				t.Errorf("expected 1 CRD, got %v", c.CRDObjects())
+			}
+			var files []string
+			for _, f := range c.Files {
+				files = append(files, f.Name)
+			}
+			if !slices.Contains(files, "files/config.ini") {
+				t.Errorf("expected file files/config.ini, got %v", files)
+			}
+			if len(c.Dependencies()) != 1 || c.Dependencies()[0].Name() != "sub" {
+				t.Errorf("expected sub chart, got %v", c.Dependencies())
+			}
+		})
+	}
+}
+
+func TestLoadChartAuth(t *testing.T) {
+	archive := chartArchive(t, map[string]string{
+		"app/Chart.yaml": "apiVersion: v2\nname: app\nversion: 1.0.0\n",
+	})
+
+	// the chart is served by another host than the repository index
+	var chartAuthorization string
+	chartServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chartAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write(archive)
+	}))
+	defer chartServer.Close()
+
+	repoServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("apiVersion: v1\nentries:\n  app:\n    - version: 1.0.0\n      urls:\n        - " + chartServer.URL + "/app-1.0.0.tgz\n"))
+	}))
+	defer repoServer.Close()
+
+	caSecret := &corev1.Secret{Data: map[string][]byte{
+		"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: repoServer.Certificate().Raw}),
+	}}
+	tlsConfig, err := tlsConfigFromSecret(caSecret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name                   string
+		auth                   *repoAuth
+		expectChartCredentials bool
+		expectedError          string
+	}{
+		{name: "no credentials", auth: nil, expectedError: "failed to download repository index"},
+		{name: "untrusted certificate", auth: &repoAuth{username: "admin", password: "secret"}, expectedError: "certificate"},
+		{name: "credentials and CA", auth: &repoAuth{username: "admin", password: "secret", tlsConfig: tlsConfig}},
+		{name: "credentials passed to chart host", auth: &repoAuth{username: "admin", password: "secret", tlsConfig: tlsConfig, passCredentials: true}, expectChartCredentials: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chartAuthorization = ""
+			_, err := loadChart(&v1alpha1.ChartInfo{Name: "app", Repo: repoServer.URL, Version: "1.0.0"}, tc.auth)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hasCredentials := chartAuthorization != ""; hasCredentials != tc.expectChartCredentials {
+				t.Errorf("expected credentials sent to the chart host: %v, got authorization %q", tc.expectChartCredentials, chartAuthorization)
+			}
+		})
+	}
+}
+
+func TestLoadOCIChart(t *testing.T) {
+	archives := map[string][]byte{}
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		archives[version] = chartArchive(t, map[string]string{
+			"app/Chart.yaml": "apiVersion: v2\nname: app\nversion: " + version + "\n",
+		})
+	}
+
+	registryHandler := ociRegistry(t, "charts/app", archives)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		registryHandler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo := "oci://" + host + "/charts"
+	dockerConfig := []byte(`{"auths": {"` + host + `": {"username": "admin", "password": "secret"}}}`)
+
+	tests := []struct {
+		name            string
+		version         string
+		auth            *repoAuth
+		expectedVersion string
+		expectedError   string
+	}{
+		{name: "exact version", version: "1.0.0", auth: &repoAuth{username: "admin", password: "secret"}, expectedVersion: "1.0.0"},
+		{name: "version range", version: "^1.0.0", auth: &repoAuth{username: "admin", password: "secret"}, expectedVersion: "1.1.0"},
+		{name: "version not found", version: "3.0.0", auth: &repoAuth{username: "admin", password: "secret"}, expectedError: "not found in repository"},
+		{name: "docker config credentials", version: "1.0.0", auth: &repoAuth{dockerConfig: dockerConfig}, expectedVersion: "1.0.0"},
+		{name: "no credentials", version: "1.0.0", expectedError: "failed to list the tags"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := loadChart(&v1alpha1.ChartInfo{Name: "app", Repo: repo, Version: tc.version, Insecure: true}, tc.auth)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.Metadata.Name != "app" || c.Metadata.Version != tc.expectedVersion {
+				t.Errorf("unexpected chart metadata %+v", c.Metadata)
+			}
+		})
+	}
+}
+
+// ociRegistry serves the chart archives, keyed by tag, with the read only part of the OCI distribution API
+func ociRegistry(t *testing.T, name string, archives map[string][]byte) http.Handler {
+	digest := func(data []byte) string {
+		return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+	}
+
+	var tags []string
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{}
+	for tag, archive := range archives {
+		config := []byte(`{"apiVersion":"v2","name":"app","version":"` + tag + `"}`)
+		manifest, err := json.Marshal(map[string]interface{}{
+			"schemaVersion": 2,
+			"mediaType":     ocispec.MediaTypeImageManifest,
+			"config":        map[string]interface{}{"mediaType": registry.ConfigMediaType, "digest": digest(config), "size": len(config)},
+			"layers":        []interface{}{map[string]interface{}{"mediaType": registry.ChartLayerMediaType, "digest": digest(archive), "size": len(archive)}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tags = append(tags, tag)
+		blobs[digest(config)] = config
+		blobs[digest(archive)] = archive
+		manifests[tag] = manifest
+		manifests[digest(manifest)] = manifest
+	}
+
+	serve := func(w http.ResponseWriter, r *http.Request, mediaType string, data []byte) {
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.Header().Set("Docker-Content-Digest", digest(data))
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(data)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := r.URL.Path; {
+		case p == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case p == "/v2/"+name+"/tags/list":
+			data, _ := json.Marshal(map[string]interface{}{"name": name, "tags": tags})
+			serve(w, r, "application/json", data)
+		case strings.HasPrefix(p, "/v2/"+name+"/manifests/"):
+			manifest, ok := manifests[strings.TrimPrefix(p, "/v2/"+name+"/manifests/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			serve(w, r, ocispec.MediaTypeImageManifest, manifest)
+		case strings.HasPrefix(p, "/v2/"+name+"/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(p, "/v2/"+name+"/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			serve(w, r, "application/octet-stream", blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func chartArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
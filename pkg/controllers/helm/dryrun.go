@@ -0,0 +1,198 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+const (
+	// maxDryRunChanges limits the number of changed paths reported per object
+	maxDryRunChanges = 20
+
+	dryRunFieldOwner = client.FieldOwner("blueprint-operator")
+)
+
+// DryRunHelmRelease renders the chart of the addon with Helm and validates the rendered objects against the
+// API server without persisting them. No HelmRelease or HelmRepository is created.
+// Errors that prevent the chart from being fetched are returned, rendering and validation errors are
+// reported in the returned status.
+func (hc *Controller) DryRunHelmRelease(ctx context.Context, addon *v1alpha1.Addon, targetNamespace string) (*v1alpha1.DryRunStatus, error) {
+	chartSpec := addon.Spec.Chart
+	result := &v1alpha1.DryRunStatus{
+		ObservedGeneration: addon.Generation,
+		LastRenderTime:     metav1.Now(),
+	}
+
+	auth, err := getRepoAuth(ctx, hc.client, chartSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	hc.logger.Info("Fetching helm chart for dry run", "Chart", chartSpec.Name, "Version", chartSpec.Version)
+	c, err := loadChart(chartSpec, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart %s: %w", chartSpec.Name, err)
+	}
+	result.ChartVersion = c.Metadata.Version
+
+	if targetNamespace == "" {
+		targetNamespace = consts.NamespaceBlueprintSystem
+	}
+
+	objs, err := hc.renderHelmRelease(ctx, c, addon, targetNamespace)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+
+	// kinds defined by the CRDs of the chart can't be validated until the CRDs are installed
+	chartKinds := map[schema.GroupKind]bool{}
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+			kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+			chartKinds[schema.GroupKind{Group: group, Kind: kind}] = true
+		}
+	}
+
+	for _, obj := range objs {
+		dryRunObj, err := hc.dryRunObject(ctx, obj, targetNamespace, chartKinds)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s %s: %s", obj.GetKind(), objectName(obj), err))
+			continue
+		}
+		result.Objects = append(result.Objects, dryRunObj)
+	}
+
+	return result, nil
+}
+
+// dryRunObject validates a rendered object with a server side dry run and compares it to the live object
+func (hc *Controller) dryRunObject(ctx context.Context, obj *unstructured.Unstructured, targetNamespace string, chartKinds map[schema.GroupKind]bool) (v1alpha1.DryRunObject, error) {
+	gvk := obj.GroupVersionKind()
+	result := v1alpha1.DryRunObject{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+	}
+
+	namespaced, err := hc.client.IsObjectNamespaced(obj)
+	if err != nil {
+		if meta.IsNoMatchError(err) && chartKinds[gvk.GroupKind()] {
+			// the CRD is part of the chart, so the object would be created along with it
+			result.Action = v1alpha1.DryRunActionCreate
+			result.Namespace = obj.GetNamespace()
+			return result, nil
+		}
+		return result, err
+	}
+	if namespaced && obj.GetNamespace() == "" {
+		obj.SetNamespace(targetNamespace)
+	}
+	result.Namespace = obj.GetNamespace()
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(gvk)
+	if err = hc.client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return result, err
+		}
+
+		result.Action = v1alpha1.DryRunActionCreate
+		if err = hc.client.Create(ctx, obj.DeepCopy(), client.DryRunAll); err != nil && !isMissingNamespace(err, obj) {
+			return result, err
+		}
+		return result, nil
+	}
+
+	desired := obj.DeepCopy()
+	if err = hc.client.Patch(ctx, desired, client.Apply, client.DryRunAll, client.ForceOwnership, dryRunFieldOwner); err != nil {
+		return result, err
+	}
+
+	result.Changes = diffObjects(live.Object, desired.Object)
+	result.Action = v1alpha1.DryRunActionUpdate
+	if len(result.Changes) == 0 {
+		result.Action = v1alpha1.DryRunActionUnchanged
+	}
+	return result, nil
+}
+
+// isMissingNamespace returns true if the error is caused by the target namespace not existing yet
+// The namespace is created when the chart is installed, so this is not a validation error.
+func isMissingNamespace(err error, obj client.Object) bool {
+	if !apierrors.IsNotFound(err) {
+		return false
+	}
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	details := status.Status().Details
+	return details.Kind == "namespaces" && details.Name == obj.GetNamespace()
+}
+
+// diffObjects returns the paths of the fields that differ between the live and the desired object
+// Fields managed by the API server are ignored.
+func diffObjects(live, desired map[string]interface{}) []string {
+	live, desired = runtimeFieldsRemoved(live), runtimeFieldsRemoved(desired)
+
+	var changes []string
+	diffValues("", live, desired, &changes)
+	sort.Strings(changes)
+	if len(changes) > maxDryRunChanges {
+		changes = append(changes[:maxDryRunChanges], fmt.Sprintf("... and %d more", len(changes)-maxDryRunChanges))
+	}
+	return changes
+}
+
+func runtimeFieldsRemoved(obj map[string]interface{}) map[string]interface{} {
+	obj = deepCopyValues(obj)
+	delete(obj, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"} {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+	return obj
+}
+
+func diffValues(path string, live, desired interface{}, changes *[]string) {
+	liveMap, liveIsMap := live.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	if !liveIsMap || !desiredIsMap {
+		if !reflect.DeepEqual(live, desired) {
+			*changes = append(*changes, path)
+		}
+		return
+	}
+
+	fields := map[string]struct{}{}
+	for k := range liveMap {
+		fields[k] = struct{}{}
+	}
+	for k := range desiredMap {
+		fields[k] = struct{}{}
+	}
+	for k := range fields {
+		diffValues(strings.TrimPrefix(path+"."+k, "."), liveMap[k], desiredMap[k], changes)
+	}
+}
+
+func objectName(obj client.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
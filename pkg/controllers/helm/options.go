@@ -0,0 +1,137 @@
+package helm
+
+import (
+	"fmt"
+	"time"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+// ValidateHelmOptions returns an error if the helm options of a chart addon are invalid
+func ValidateHelmOptions(opts *v1alpha1.HelmOptions) error {
+	spec := &helmv2.HelmReleaseSpec{
+		Install: &helmv2.Install{},
+		Upgrade: &helmv2.Upgrade{},
+	}
+	return applyHelmOptions(spec, opts)
+}
+
+// applyHelmOptions overrides the defaults of the HelmRelease spec with the helm options of a chart addon
+// The install and upgrade actions of the spec must be set.
+func applyHelmOptions(spec *helmv2.HelmReleaseSpec, opts *v1alpha1.HelmOptions) error {
+	if opts == nil {
+		return nil
+	}
+
+	if opts.Wait {
+		spec.Install.DisableWait = false
+		spec.Upgrade.DisableWait = false
+	}
+
+	if opts.Timeout != "" {
+		timeout, err := parseDuration("timeout", opts.Timeout)
+		if err != nil {
+			return err
+		}
+		spec.Timeout = timeout
+	}
+
+	if opts.InstallRetries != nil {
+		if spec.Install.Remediation == nil {
+			spec.Install.Remediation = &helmv2.InstallRemediation{}
+		}
+		spec.Install.Remediation.Retries = int(*opts.InstallRetries)
+	}
+
+	if opts.UpgradeRetries != nil || opts.RemediationStrategy != "" {
+		if spec.Upgrade.Remediation == nil {
+			spec.Upgrade.Remediation = &helmv2.UpgradeRemediation{}
+		}
+	}
+	if opts.UpgradeRetries != nil {
+		spec.Upgrade.Remediation.Retries = int(*opts.UpgradeRetries)
+	}
+
+	switch strategy := helmv2.RemediationStrategy(opts.RemediationStrategy); strategy {
+	case "":
+	case helmv2.RollbackRemediationStrategy, helmv2.UninstallRemediationStrategy:
+		spec.Upgrade.Remediation.Strategy = &strategy
+	default:
+		return fmt.Errorf("invalid remediation strategy %q, must be one of %s or %s", strategy, helmv2.RollbackRemediationStrategy, helmv2.UninstallRemediationStrategy)
+	}
+
+	switch policy := helmv2.CRDsPolicy(opts.CRDs); policy {
+	case "":
+	case helmv2.Skip, helmv2.Create, helmv2.CreateReplace:
+		spec.Install.CRDs = policy
+		spec.Upgrade.CRDs = policy
+	default:
+		return fmt.Errorf("invalid CRDs policy %q, must be one of %s, %s or %s", policy, helmv2.Skip, helmv2.Create, helmv2.CreateReplace)
+	}
+
+	if opts.DisableHooks {
+		spec.Install.DisableHooks = true
+		spec.Upgrade.DisableHooks = true
+		spec.Uninstall = uninstallAction(spec)
+		spec.Uninstall.DisableHooks = true
+	}
+
+	if opts.DriftDetection != nil {
+		drift, err := driftDetection(opts.DriftDetection)
+		if err != nil {
+			return err
+		}
+		spec.DriftDetection = drift
+	}
+
+	if opts.Interval != "" {
+		interval, err := parseDuration("interval", opts.Interval)
+		if err != nil {
+			return err
+		}
+		spec.Interval = *interval
+	}
+
+	return nil
+}
+
+// driftDetection converts the drift detection options of a chart addon, the mode defaulting to enabled
+func driftDetection(opts *v1alpha1.DriftDetection) (*helmv2.DriftDetection, error) {
+	drift := &helmv2.DriftDetection{Mode: helmv2.DriftDetectionEnabled}
+
+	switch mode := helmv2.DriftDetectionMode(opts.Mode); mode {
+	case "":
+	case helmv2.DriftDetectionEnabled, helmv2.DriftDetectionWarn, helmv2.DriftDetectionDisabled:
+		drift.Mode = mode
+	default:
+		return nil, fmt.Errorf("invalid drift detection mode %q, must be one of %s, %s or %s", mode, helmv2.DriftDetectionEnabled, helmv2.DriftDetectionWarn, helmv2.DriftDetectionDisabled)
+	}
+
+	for i, rule := range opts.Ignore {
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("drift detection ignore rule %d has no paths", i)
+		}
+		drift.Ignore = append(drift.Ignore, helmv2.IgnoreRule{Paths: rule.Paths, Target: convertSelector(rule.Target)})
+	}
+
+	return drift, nil
+}
+
+// uninstallAction returns the uninstall action of the spec, creating it if needed
+func uninstallAction(spec *helmv2.HelmReleaseSpec) *helmv2.Uninstall {
+	if spec.Uninstall == nil {
+		return &helmv2.Uninstall{}
+	}
+	return spec.Uninstall
+}
+
+func parseDuration(field, value string) (*metav1.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", field, value, err)
+	}
+	return &metav1.Duration{Duration: d}, nil
+}
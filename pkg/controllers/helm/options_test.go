@@ -0,0 +1,131 @@
+package helm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/kustomize"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+func TestApplyHelmOptions(t *testing.T) {
+	defaultSpec := func() helmv2.HelmReleaseSpec {
+		rollback := helmv2.RollbackRemediationStrategy
+		return helmv2.HelmReleaseSpec{
+			Install: &helmv2.Install{
+				DisableWait:     true,
+				CreateNamespace: true,
+				Remediation:     &helmv2.InstallRemediation{Retries: installationRetries},
+			},
+			Upgrade: &helmv2.Upgrade{
+				DisableWait:   true,
+				CleanupOnFail: true,
+				Remediation:   &helmv2.UpgradeRemediation{Retries: upgradeRetries, Strategy: &rollback},
+			},
+			DriftDetection: &helmv2.DriftDetection{Mode: helmv2.DriftDetectionEnabled},
+			Interval:       metav1.Duration{Duration: driftDetectionInterval},
+		}
+	}
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	tests := []struct {
+		name          string
+		opts          *v1alpha1.HelmOptions
+		expected      func(spec *helmv2.HelmReleaseSpec)
+		expectedError string
+	}{
+		{
+			name:     "defaults",
+			expected: func(spec *helmv2.HelmReleaseSpec) {},
+		},
+		{
+			name: "wait and timeout",
+			opts: &v1alpha1.HelmOptions{Wait: true, Timeout: "10m"},
+			expected: func(spec *helmv2.HelmReleaseSpec) {
+				spec.Install.DisableWait = false
+				spec.Upgrade.DisableWait = false
+				spec.Timeout = &metav1.Duration{Duration: 10 * time.Minute}
+			},
+		},
+		{
+			name: "retries and remediation",
+			opts: &v1alpha1.HelmOptions{InstallRetries: int32Ptr(0), UpgradeRetries: int32Ptr(-1), RemediationStrategy: "uninstall"},
+			expected: func(spec *helmv2.HelmReleaseSpec) {
+				uninstall := helmv2.UninstallRemediationStrategy
+				spec.Install.Remediation.Retries = 0
+				spec.Upgrade.Remediation.Retries = -1
+				spec.Upgrade.Remediation.Strategy = &uninstall
+			},
+		},
+		{
+			name: "CRDs and hooks",
+			opts: &v1alpha1.HelmOptions{CRDs: "CreateReplace", DisableHooks: true},
+			expected: func(spec *helmv2.HelmReleaseSpec) {
+				spec.Install.CRDs = helmv2.CreateReplace
+				spec.Upgrade.CRDs = helmv2.CreateReplace
+				spec.Install.DisableHooks = true
+				spec.Upgrade.DisableHooks = true
+				spec.Uninstall = &helmv2.Uninstall{DisableHooks: true}
+			},
+		},
+		{
+			name: "drift detection and interval",
+			opts: &v1alpha1.HelmOptions{
+				DriftDetection: &v1alpha1.DriftDetection{
+					Mode:   "warn",
+					Ignore: []v1alpha1.DriftIgnoreRule{{Paths: []string{"/spec/replicas"}, Target: &v1alpha1.Selector{Kind: "Deployment"}}},
+				},
+				Interval: "5m",
+			},
+			expected: func(spec *helmv2.HelmReleaseSpec) {
+				spec.DriftDetection = &helmv2.DriftDetection{
+					Mode:   helmv2.DriftDetectionWarn,
+					Ignore: []helmv2.IgnoreRule{{Paths: []string{"/spec/replicas"}, Target: &kustomize.Selector{Kind: "Deployment"}}},
+				}
+				spec.Interval = metav1.Duration{Duration: 5 * time.Minute}
+			},
+		},
+		{
+			name:          "invalid timeout",
+			opts:          &v1alpha1.HelmOptions{Timeout: "ten minutes"},
+			expectedError: "invalid timeout",
+		},
+		{
+			name:          "invalid CRDs policy",
+			opts:          &v1alpha1.HelmOptions{CRDs: "Replace"},
+			expectedError: "invalid CRDs policy",
+		},
+		{
+			name:          "ignore rule without paths",
+			opts:          &v1alpha1.HelmOptions{DriftDetection: &v1alpha1.DriftDetection{Ignore: []v1alpha1.DriftIgnoreRule{{}}}},
+			expectedError: "has no paths",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := defaultSpec()
+			err := applyHelmOptions(&spec, tc.opts)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := defaultSpec()
+			tc.expected(&expected)
+			if !reflect.DeepEqual(spec, expected) {
+				t.Errorf("expected spec %+v, got %+v", expected, spec)
+			}
+		})
+	}
+}
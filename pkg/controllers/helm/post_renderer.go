@@ -0,0 +1,68 @@
+package helm
+
+import (
+	"fmt"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/kustomize"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+// ValidatePostRenderer returns an error if the kustomize values of a chart addon can't be used as a post-renderer
+func ValidatePostRenderer(values *v1alpha1.Values) error {
+	_, err := postRenderers(values)
+	return err
+}
+
+// postRenderers translates the kustomize values of a chart addon to the kustomize post-renderer of a HelmRelease
+// Patch files, patch options and image tag suffixes are not supported by the helm-controller.
+func postRenderers(values *v1alpha1.Values) ([]helmv2.PostRenderer, error) {
+	if values == nil || (len(values.Patches) == 0 && len(values.Images) == 0) {
+		return nil, nil
+	}
+
+	k := &helmv2.Kustomize{}
+	for i, p := range values.Patches {
+		if p.Path != "" {
+			return nil, fmt.Errorf("patch %d: patch files are not supported for charts, the patch must be inline", i)
+		}
+		if len(p.Options) > 0 {
+			return nil, fmt.Errorf("patch %d: patch options are not supported for charts", i)
+		}
+		k.Patches = append(k.Patches, kustomize.Patch{
+			Patch:  p.Patch,
+			Target: convertSelector(p.Target),
+		})
+	}
+
+	for _, i := range values.Images {
+		if i.TagSuffix != "" {
+			return nil, fmt.Errorf("image %s: tag suffixes are not supported for charts", i.Name)
+		}
+		k.Images = append(k.Images, kustomize.Image{
+			Name:    i.Name,
+			NewName: i.NewName,
+			NewTag:  i.NewTag,
+			Digest:  i.Digest,
+		})
+	}
+
+	return []helmv2.PostRenderer{{Kustomize: k}}, nil
+}
+
+func convertSelector(target *v1alpha1.Selector) *kustomize.Selector {
+	if target == nil {
+		return nil
+	}
+
+	return &kustomize.Selector{
+		Group:              target.Group,
+		Version:            target.Version,
+		Kind:               target.Kind,
+		Namespace:          target.Namespace,
+		Name:               target.Name,
+		AnnotationSelector: target.AnnotationSelector,
+		LabelSelector:      target.LabelSelector,
+	}
+}
@@ -0,0 +1,75 @@
+package helm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/kustomize"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+func TestPostRenderers(t *testing.T) {
+	tolerations := `[{"op": "add", "path": "/spec/template/spec/tolerations", "value": [{"operator": "Exists"}]}]`
+
+	tests := []struct {
+		name          string
+		values        *v1alpha1.Values
+		expected      []helmv2.PostRenderer
+		expectedError string
+	}{
+		{
+			name: "no values",
+		},
+		{
+			name:   "empty values",
+			values: &v1alpha1.Values{},
+		},
+		{
+			name: "patches and images",
+			values: &v1alpha1.Values{
+				Patches: []v1alpha1.Patch{{Patch: tolerations, Target: &v1alpha1.Selector{Kind: "Deployment", Name: "app"}}},
+				Images:  []v1alpha1.Image{{Name: "docker.io/app", NewName: "mirror.example.com/app", NewTag: "1.2.3"}},
+			},
+			expected: []helmv2.PostRenderer{{Kustomize: &helmv2.Kustomize{
+				Patches: []kustomize.Patch{{Patch: tolerations, Target: &kustomize.Selector{Kind: "Deployment", Name: "app"}}},
+				Images:  []kustomize.Image{{Name: "docker.io/app", NewName: "mirror.example.com/app", NewTag: "1.2.3"}},
+			}}},
+		},
+		{
+			name:          "patch file",
+			values:        &v1alpha1.Values{Patches: []v1alpha1.Patch{{Path: "patch.yaml"}}},
+			expectedError: "patch files are not supported",
+		},
+		{
+			name:          "patch options",
+			values:        &v1alpha1.Values{Patches: []v1alpha1.Patch{{Patch: tolerations, Options: map[string]bool{"allowNameChange": true}}}},
+			expectedError: "patch options are not supported",
+		},
+		{
+			name:          "image tag suffix",
+			values:        &v1alpha1.Values{Images: []v1alpha1.Image{{Name: "app", TagSuffix: "-fips"}}},
+			expectedError: "tag suffixes are not supported",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			renderers, err := postRenderers(tc.values)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(renderers, tc.expected) {
+				t.Errorf("expected post renderers %+v, got %+v", tc.expected, renderers)
+			}
+		})
+	}
+}
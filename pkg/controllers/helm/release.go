@@ -10,7 +10,10 @@ import (
 	"github.com/fluxcd/pkg/apis/meta"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -45,19 +48,24 @@ type Controller struct {
 	k8sClient *k8s.Client
 	client    client.Client
 	logger    logr.Logger
+
+	// restConfig is used by Helm to read the capabilities of the cluster during dry runs
+	// Without it, charts are rendered with the default capabilities of Helm and lookup returns nothing.
+	restConfig *rest.Config
 }
 
-func NewHelmChartController(client client.Client, k8sClient *k8s.Client, logger logr.Logger) *Controller {
+func NewHelmChartController(client client.Client, k8sClient *k8s.Client, restConfig *rest.Config, logger logr.Logger) *Controller {
 	return &Controller{
-		client:    client,
-		k8sClient: k8sClient,
-		logger:    logger,
+		client:     client,
+		k8sClient:  k8sClient,
+		restConfig: restConfig,
+		logger:     logger,
 	}
 }
 
 // CreateHelmRelease creates a HelmRelease object in the given namespace
-func (hc *Controller) CreateHelmRelease(ctx context.Context, addon *v1alpha1.Addon, targetNamespace string, isDryRun bool) error {
-	repoName := getRepoName(addon)
+func (hc *Controller) CreateHelmRelease(ctx context.Context, addon *v1alpha1.Addon, targetNamespace string) error {
+	repoName := RepoName(addon)
 	releaseName := addon.Spec.Name
 	chartSpec := addon.Spec.Chart
 
@@ -73,14 +81,46 @@ func (hc *Controller) CreateHelmRelease(ctx context.Context, addon *v1alpha1.Add
 			Namespace: consts.NamespaceBlueprintSystem,
 		},
 		Spec: sourcev1.HelmRepositorySpec{
-			URL:  chartSpec.Repo,
+			URL:  NormalizeRepoURL(chartSpec.Repo),
 			Type: helmRepoType,
 			Interval: metav1.Duration{
 				Duration: helmRepoInterval,
 			},
+			SecretRef:       localObjectReference(chartSpec.SecretRef),
+			CertSecretRef:   localObjectReference(chartSpec.CertSecretRef),
+			PassCredentials: chartSpec.PassCredentials,
+			Insecure:        chartSpec.Insecure,
 		},
 	}
 
+	values, err := MergeSetValues(chartSpec.Values, chartSpec.Set)
+	if err != nil {
+		return fmt.Errorf("failed to merge set values for addon %q: %w", addon.Name, err)
+	}
+
+	// the digest of the referenced values is used to request a reconciliation of the HelmRelease whenever they
+	// change, since the helm-controller doesn't watch them
+	_, valuesDigest, err := ResolveValuesFrom(ctx, hc.client, chartSpec.ValuesFrom)
+	if err != nil {
+		return fmt.Errorf("failed to resolve values for addon %q: %w", addon.Name, err)
+	}
+
+	var valuesFrom []helmv2.ValuesReference
+	for _, ref := range chartSpec.ValuesFrom {
+		valuesFrom = append(valuesFrom, helmv2.ValuesReference{
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+			ValuesKey:  ref.ValuesKey,
+			TargetPath: ref.TargetPath,
+			Optional:   ref.Optional,
+		})
+	}
+
+	postRenderers, err := postRenderers(chartSpec.Kustomize)
+	if err != nil {
+		return fmt.Errorf("invalid kustomize values for addon %q: %w", addon.Name, err)
+	}
+
 	var dependsOn []meta.NamespacedObjectReference
 	for _, addonName := range chartSpec.DependsOn {
 		dependsOn = append(dependsOn, meta.NamespacedObjectReference{
@@ -104,7 +144,7 @@ func (hc *Controller) CreateHelmRelease(ctx context.Context, addon *v1alpha1.Add
 					Version: chartSpec.Version,
 					SourceRef: helmv2.CrossNamespaceObjectReference{
 						Name: repoName,
-						Kind: "HelmRepository",
+						Kind: KindHelmRepository,
 					},
 					ReconcileStrategy: "Revision",
 				},
@@ -127,7 +167,9 @@ func (hc *Controller) CreateHelmRelease(ctx context.Context, addon *v1alpha1.Add
 			DriftDetection: &helmv2.DriftDetection{
 				Mode: helmv2.DriftDetectionEnabled,
 			},
-			Values: chartSpec.Values,
+			Values:        values,
+			ValuesFrom:    valuesFrom,
+			PostRenderers: postRenderers,
 			Interval: metav1.Duration{
 				Duration: driftDetectionInterval,
 			},
@@ -135,32 +177,55 @@ func (hc *Controller) CreateHelmRelease(ctx context.Context, addon *v1alpha1.Add
 		},
 	}
 
+	if err = applyHelmOptions(&release.Spec, chartSpec.HelmOptions); err != nil {
+		return fmt.Errorf("invalid helm options for addon %q: %w", addon.Name, err)
+	}
+
+	release.Annotations = releaseAnnotations(addon, valuesDigest)
+
 	// set owner reference
 	if err := controllerutil.SetControllerReference(addon, release, hc.client.Scheme()); err != nil {
 		return fmt.Errorf("failed to set owner reference for addon %q: %w", addon.Name, err)
 	}
 
-	if isDryRun {
-		// TODO - Jira Ticket: https://mirantis.jira.com/browse/BOP-585
+	if err = hc.applyHelmRelease(ctx, addon, repo, release); err != nil {
+		return err
 	}
 
-	return hc.applyHelmRelease(ctx, repo, release)
+	// the addon may have been using another repository before its chart spec changed
+	return hc.releaseRepositories(ctx, addon, repo.Name)
 }
 
-// DeleteHelmRelease deletes a HelmRelease object in the given namespace
-func (hc *Controller) DeleteHelmRelease(ctx context.Context, addon *v1alpha1.Addon) error {
-	release := &helmv2.HelmRelease{
-		TypeMeta: helmReleaseTypeMeta,
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      addon.Spec.Chart.Name,
-			Namespace: consts.NamespaceBlueprintSystem,
-		},
+// DeleteHelmRelease deletes the HelmRelease of the addon and releases its HelmRepository
+// It returns whether the HelmRelease is gone. Until then, the helm-controller is still uninstalling the release and
+// the repository is kept.
+func (hc *Controller) DeleteHelmRelease(ctx context.Context, addon *v1alpha1.Addon) (bool, error) {
+	release := &helmv2.HelmRelease{}
+	key := types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: addon.Spec.Name}
+	if err := hc.client.Get(ctx, key, release); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get helm release: %w", err)
+		}
+
+		// the repository is only deleted if no other addon uses it
+		return true, hc.releaseRepositories(ctx, addon, "")
 	}
 
-	repo := &sourcev1.HelmRepository{
-		TypeMeta: helmRepositoryTypeMeta,
+	if release.DeletionTimestamp.IsZero() {
+		if err := hc.k8sClient.Delete(ctx, release); err != nil {
+			return false, fmt.Errorf("failed to delete helm release: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// UninstallHelmRelease deletes the HelmRelease of the addon, which uninstalls the release
+// Unlike DeleteHelmRelease, the HelmRepository is kept, since the addon still exists.
+func (hc *Controller) UninstallHelmRelease(ctx context.Context, addon *v1alpha1.Addon) error {
+	release := &helmv2.HelmRelease{
+		TypeMeta: helmReleaseTypeMeta,
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      getRepoName(addon),
+			Name:      addon.Spec.Name,
 			Namespace: consts.NamespaceBlueprintSystem,
 		},
 	}
@@ -168,21 +233,20 @@ func (hc *Controller) DeleteHelmRelease(ctx context.Context, addon *v1alpha1.Add
 	if err := hc.k8sClient.Delete(ctx, release); err != nil {
 		return fmt.Errorf("failed to delete helm release: %w", err)
 	}
-
-	if err := hc.k8sClient.Delete(ctx, repo); err != nil {
-		return fmt.Errorf("failed to delete helm repository: %w", err)
-	}
-
 	return nil
 }
 
-func (hc *Controller) applyHelmRelease(ctx context.Context, repo *sourcev1.HelmRepository, release *helmv2.HelmRelease) error {
+func (hc *Controller) applyHelmRelease(ctx context.Context, addon *v1alpha1.Addon, repo *sourcev1.HelmRepository, release *helmv2.HelmRelease) error {
 
-	hc.logger.Info("Applying helm repo", "HelmRepo", release.GetName())
+	hc.logger.Info("Applying helm repo", "HelmRepo", repo.GetName())
 	if err := hc.k8sClient.Apply(ctx, repo); err != nil {
 		return fmt.Errorf("failed to create or update helm repository: %w", err)
 	}
 
+	if err := hc.acquireRepository(ctx, addon, repo); err != nil {
+		return err
+	}
+
 	hc.logger.Info("Applying helm release", "HelmReleaseName", release.GetName())
 	if err := hc.k8sClient.Apply(ctx, release); err != nil {
 		return fmt.Errorf("failed to create or update helm release: %w", err)
@@ -191,7 +255,10 @@ func (hc *Controller) applyHelmRelease(ctx context.Context, repo *sourcev1.HelmR
 	return nil
 }
 
-// getRepoName returns the name of the HelmRepository object
-func getRepoName(addon *v1alpha1.Addon) string {
-	return fmt.Sprintf("repo-%s-%s", addon.Name, addon.Spec.Chart.Name)
+// localObjectReference converts a reference to a Secret of the blueprint-system namespace to a flux reference
+func localObjectReference(ref *v1alpha1.SecretReference) *meta.LocalObjectReference {
+	if ref == nil {
+		return nil
+	}
+	return &meta.LocalObjectReference{Name: ref.Name}
 }
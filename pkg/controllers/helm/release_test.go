@@ -0,0 +1,94 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+	k8s "github.com/mirantiscontainers/blueprint-operator/pkg/kubernetes"
+)
+
+func TestDeleteHelmRelease(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{v1alpha1.AddToScheme, sourcev1.AddToScheme, helmv2.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the name of the addon differs from the name of its chart
+	addon := &v1alpha1.Addon{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: consts.NamespaceBlueprintSystem, UID: "web-uid"},
+		Spec: v1alpha1.AddonSpec{
+			Name:  "web",
+			Kind:  "chart",
+			Chart: &v1alpha1.ChartInfo{Name: "nginx", Repo: "https://charts.bitnami.com/bitnami", Version: "1.0.0"},
+		},
+	}
+	release := &helmv2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "web",
+			Namespace:  consts.NamespaceBlueprintSystem,
+			Finalizers: []string{"finalizers.fluxcd.io"},
+		},
+	}
+	repo := &sourcev1.HelmRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            RepoName(addon),
+			Namespace:       consts.NamespaceBlueprintSystem,
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "blueprint.mirantis.com/v1alpha1", Kind: "Addon", Name: "web", UID: addon.UID}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(addon, release, repo).Build()
+	hc := NewHelmChartController(c, k8s.NewClient(logr.Discard(), c), nil, logr.Discard())
+	ctx := context.TODO()
+
+	// the release is kept by the finalizer of the helm-controller while it is being uninstalled
+	for i := 0; i < 2; i++ {
+		deleted, err := hc.DeleteHelmRelease(ctx, addon)
+		if err != nil {
+			t.Fatalf("failed to delete helm release: %v", err)
+		}
+		if deleted {
+			t.Fatalf("expected the release to be reported as not deleted while it is being uninstalled")
+		}
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(release), release); err != nil {
+		t.Fatalf("failed to get helm release: %v", err)
+	}
+	if release.DeletionTimestamp.IsZero() {
+		t.Fatalf("expected the release of the addon to be deleted")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(repo), &sourcev1.HelmRepository{}); err != nil {
+		t.Fatalf("expected the repository to be kept while the release is uninstalled, got %v", err)
+	}
+
+	release.Finalizers = nil
+	if err := c.Update(ctx, release); err != nil {
+		t.Fatalf("failed to remove the finalizer of the helm release: %v", err)
+	}
+
+	deleted, err := hc.DeleteHelmRelease(ctx, addon)
+	if err != nil {
+		t.Fatalf("failed to delete helm release: %v", err)
+	}
+	if !deleted {
+		t.Errorf("expected the release to be reported as deleted once it is gone")
+	}
+	key := types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: repo.Name}
+	if err := c.Get(ctx, key, &sourcev1.HelmRepository{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the repository to be deleted with its last user, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/kustomize"
+)
+
+// renderHelmRelease renders the chart with the values of the addon and returns the resulting objects
+// The chart is rendered by a Helm install dry run. With a REST config, the capabilities are read from the
+// API server and the lookup function queries the cluster, otherwise the default capabilities of Helm are used.
+// Nothing is installed and no release is stored in the cluster.
+func (hc *Controller) renderHelmRelease(ctx context.Context, c *chart.Chart, addon *v1alpha1.Addon, targetNamespace string) ([]*unstructured.Unstructured, error) {
+	merged, err := MergeSetValues(addon.Spec.Chart.Values, addon.Spec.Chart.Set)
+	if err != nil {
+		return nil, err
+	}
+
+	inlineValues := map[string]interface{}{}
+	if merged != nil && len(merged.Raw) > 0 {
+		if err = json.Unmarshal(merged.Raw, &inlineValues); err != nil {
+			return nil, fmt.Errorf("failed to parse values: %w", err)
+		}
+	}
+
+	// as with the helm-controller, inline values take precedence over the referenced values
+	userValues, _, err := ResolveValuesFrom(ctx, hc.client, addon.Spec.Chart.ValuesFrom)
+	if err != nil {
+		return nil, err
+	}
+	userValues = mergeValues(userValues, inlineValues)
+
+	cfg := &action.Configuration{
+		KubeClient: &kubefake.PrintingKubeClient{Out: io.Discard},
+		Releases:   storage.Init(driver.NewMemory()),
+		Log: func(format string, v ...interface{}) {
+			hc.logger.V(1).Info(fmt.Sprintf(format, v...))
+		},
+	}
+
+	install := action.NewInstall(cfg)
+	install.ReleaseName = addon.Spec.Name
+	install.Namespace = targetNamespace
+	install.DryRun = true
+	if hc.restConfig != nil {
+		cfg.RESTClientGetter = &restClientGetter{config: hc.restConfig}
+		install.DryRunOption = "server"
+	} else {
+		install.ClientOnly = true
+	}
+	if addon.Spec.Chart.Kustomize != nil {
+		install.PostRenderer = &kustomizePostRenderer{logger: hc.logger, values: addon.Spec.Chart.Kustomize}
+	}
+
+	// the CRDs are read from the chart, as with Helm the post-renderer is not applied to them
+	var crds []*unstructured.Unstructured
+	for _, crd := range c.CRDObjects() {
+		objs, err := decodeManifest(crd.Filename, string(crd.File.Data))
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, objs...)
+	}
+
+	rel, err := install.RunWithContext(ctx, c, userValues)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := decodeManifest(addon.Spec.Name, rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(crds, objs...), nil
+}
+
+// kustomizePostRenderer applies the kustomize values of a chart addon to the manifests rendered by Helm
+type kustomizePostRenderer struct {
+	logger logr.Logger
+	values *v1alpha1.Values
+}
+
+func (r *kustomizePostRenderer) Run(manifests *bytes.Buffer) (*bytes.Buffer, error) {
+	rendered, err := kustomize.PostRender(r.logger, manifests.Bytes(), r.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post-render chart: %w", err)
+	}
+	return bytes.NewBuffer(rendered), nil
+}
+
+// restClientGetter provides Helm with the clients of the cluster the operator runs in
+type restClientGetter struct {
+	config *rest.Config
+}
+
+func (g *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.CopyConfig(g.config), nil
+}
+
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(dc), nil
+}
+
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	dc, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(dc), nil
+}
+
+// decodeManifest decodes a multi document YAML manifest into objects
+func decodeManifest(name, manifest string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for i, doc := range splitYAMLDocuments(manifest) {
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("failed to decode document %d of %s: %w", i, name, err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetAPIVersion() == "" || u.GetKind() == "" {
+			return nil, fmt.Errorf("document %d of %s is missing apiVersion or kind", i, name)
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// splitYAMLDocuments splits a multi document YAML string at the document separators
+func splitYAMLDocuments(content string) []string {
+	var docs []string
+	var current strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "---") && strings.TrimSpace(strings.TrimLeft(line, "-")) == "" {
+			docs = append(docs, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	return append(docs, current.String())
+}
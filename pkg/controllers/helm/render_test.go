@@ -0,0 +1,269 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+func TestRenderHelmRelease(t *testing.T) {
+	archive := chartArchive(t, map[string]string{
+		"app/Chart.yaml": `
+apiVersion: v2
+name: app
+version: 1.0.0
+dependencies:
+  - name: cache
+    version: 0.1.0
+    condition: cache.enabled
+`,
+		"app/values.yaml": `
+replicas: 1
+image:
+  repository: nginx
+  tag: "1.25"
+labels: {}
+cache:
+  enabled: false
+`,
+		"app/templates/_helpers.tpl": `{{- define "app.fullname" -}}{{ .Release.Name }}-{{ .Chart.Name }}{{- end -}}`,
+		"app/templates/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "app.fullname" . }}
+  namespace: {{ .Release.Namespace }}
+  {{- with .Values.labels }}
+  labels:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+        - name: app
+          image: {{ printf "%s:%s" .Values.image.repository .Values.image.tag | quote }}
+`,
+		"app/templates/secret.yaml": `
+{{- $ca := genCA "app-ca" 365 }}
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ include "app.fullname" . }}-ca
+data:
+  ca.crt: {{ $ca.Cert | b64enc }}
+  {{- if semverCompare ">=1.0.0-0" .Capabilities.KubeVersion.Version }}
+  supported: {{ "true" | b64enc }}
+  {{- end }}
+`,
+		"app/crds/crd.yaml": `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: caches.example.com
+spec:
+  group: example.com
+  names:
+    kind: Cache
+    plural: caches
+`,
+		"app/charts/cache/Chart.yaml":         "apiVersion: v2\nname: cache\nversion: 0.1.0\n",
+		"app/charts/cache/values.yaml":        "port: 6379\n",
+		"app/charts/cache/templates/svc.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: cache\nspec:\n  ports:\n    - port: {{ .Values.port }}\n",
+	})
+
+	tests := []struct {
+		name          string
+		chart         *v1alpha1.ChartInfo
+		expectedKinds []string
+		expected      []string
+	}{
+		{
+			name:          "chart defaults",
+			chart:         &v1alpha1.ChartInfo{},
+			expectedKinds: []string{"CustomResourceDefinition", "Secret", "Deployment"},
+			expected:      []string{"name: test-app", "namespace: ns", "replicas: 1", "image: nginx:1.25", "supported:"},
+		},
+		{
+			name: "user values override defaults",
+			chart: &v1alpha1.ChartInfo{
+				Values: &apiextensionsv1.JSON{Raw: []byte(`{"replicas": 3, "labels": {"team": "a"}}`)},
+				Set:    map[string]intstr.IntOrString{"image.tag": intstr.FromString("1.26")},
+			},
+			expectedKinds: []string{"CustomResourceDefinition", "Secret", "Deployment"},
+			expected:      []string{"replicas: 3", "team: a", "image: nginx:1.26"},
+		},
+		{
+			name: "enabled sub chart",
+			chart: &v1alpha1.ChartInfo{
+				Values: &apiextensionsv1.JSON{Raw: []byte(`{"cache": {"enabled": true, "port": 7000}}`)},
+			},
+			expectedKinds: []string{"CustomResourceDefinition", "Secret", "Service", "Deployment"},
+			expected:      []string{"port: 7000"},
+		},
+		{
+			name: "kustomize post-renderer",
+			chart: &v1alpha1.ChartInfo{
+				Kustomize: &v1alpha1.Values{Images: []v1alpha1.Image{{Name: "nginx", NewName: "mirror.example.com/nginx"}}},
+			},
+			expectedKinds: []string{"CustomResourceDefinition", "Secret", "Deployment"},
+			expected:      []string{"image: mirror.example.com/nginx:1.25"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := loader.LoadArchive(bytes.NewReader(archive))
+			if err != nil {
+				t.Fatalf("failed to load chart: %v", err)
+			}
+
+			hc := &Controller{logger: logr.Discard()}
+			addon := &v1alpha1.Addon{Spec: v1alpha1.AddonSpec{Name: "test", Chart: tc.chart}}
+			objs, err := hc.renderHelmRelease(context.TODO(), c, addon, "ns")
+			if err != nil {
+				t.Fatalf("failed to render chart: %v", err)
+			}
+
+			var kinds []string
+			var content []string
+			for _, o := range objs {
+				kinds = append(kinds, o.GetKind())
+				data, _ := yaml.Marshal(o.Object)
+				content = append(content, string(data))
+			}
+			if !reflect.DeepEqual(kinds, tc.expectedKinds) {
+				t.Errorf("expected kinds %v, got %v", tc.expectedKinds, kinds)
+			}
+			for _, e := range tc.expected {
+				if !strings.Contains(strings.Join(content, "---\n"), e) {
+					t.Errorf("expected rendered chart to contain %q, got:\n%s", e, strings.Join(content, "---\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHelmReleaseWithCluster(t *testing.T) {
+	archive := chartArchive(t, map[string]string{
+		"app/Chart.yaml": "apiVersion: v2\nname: app\nversion: 1.0.0\n",
+		"app/templates/config.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  kubeVersion: {{ .Capabilities.KubeVersion.Version | quote }}
+  hasDeployments: {{ .Capabilities.APIVersions.Has "apps/v1/Deployment" | quote }}
+  {{- with (lookup "v1" "ConfigMap" "ns" "settings") }}
+  mode: {{ .data.mode | quote }}
+  {{- end }}
+`,
+	})
+
+	// a minimal API server, serving discovery and the ConfigMap looked up by the chart
+	responses := map[string]string{
+		"/version": `{"major": "1", "minor": "29", "gitVersion": "v1.29.4"}`,
+		"/api":     `{"kind": "APIVersions", "versions": ["v1"]}`,
+		"/apis": `{"kind": "APIGroupList", "apiVersion": "v1", "groups": [{"name": "apps", ` +
+			`"versions": [{"groupVersion": "apps/v1", "version": "v1"}], "preferredVersion": {"groupVersion": "apps/v1", "version": "v1"}}]}`,
+		"/api/v1": `{"kind": "APIResourceList", "groupVersion": "v1", "resources": [` +
+			`{"name": "configmaps", "singularName": "configmap", "namespaced": true, "kind": "ConfigMap", "verbs": ["get", "list"]}]}`,
+		"/apis/apps/v1": `{"kind": "APIResourceList", "groupVersion": "apps/v1", "resources": [` +
+			`{"name": "deployments", "singularName": "deployment", "namespaced": true, "kind": "Deployment", "verbs": ["get", "list"]}]}`,
+		"/api/v1/namespaces/ns/configmaps/settings": `{"apiVersion": "v1", "kind": "ConfigMap", ` +
+			`"metadata": {"name": "settings", "namespace": "ns"}, "data": {"mode": "production"}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	c, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("failed to load chart: %v", err)
+	}
+
+	hc := &Controller{logger: logr.Discard(), restConfig: &rest.Config{Host: server.URL}}
+	addon := &v1alpha1.Addon{Spec: v1alpha1.AddonSpec{Name: "test", Chart: &v1alpha1.ChartInfo{}}}
+	objs, err := hc.renderHelmRelease(context.TODO(), c, addon, "ns")
+	if err != nil {
+		t.Fatalf("failed to render chart: %v", err)
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 object, got %d", len(objs))
+	}
+
+	data, _, _ := unstructured.NestedStringMap(objs[0].Object, "data")
+	expected := map[string]string{"kubeVersion": "v1.29.4", "hasDeployments": "true", "mode": "production"}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected data %v, got %v", expected, data)
+	}
+}
+
+func TestKustomizePostRenderer(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "app"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "app", "image": "docker.io/app:1.0.0"}},
+				},
+			},
+		},
+	}}
+
+	values := &v1alpha1.Values{
+		Patches: []v1alpha1.Patch{{Patch: `[{"op": "add", "path": "/spec/template/spec/tolerations", "value": [{"operator": "Exists"}]}]`, Target: &v1alpha1.Selector{Kind: "Deployment"}}},
+		Images:  []v1alpha1.Image{{Name: "docker.io/app", NewName: "mirror.example.com/app"}},
+	}
+
+	manifest, err := yaml.Marshal(deployment.Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &kustomizePostRenderer{logger: logr.Discard(), values: values}
+	rendered, err := r.Run(bytes.NewBuffer(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	objs, err := decodeManifest("post-renderer", rendered.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 object, got %d", len(objs))
+	}
+
+	containers, _, _ := unstructured.NestedSlice(objs[0].Object, "spec", "template", "spec", "containers")
+	if image := containers[0].(map[string]interface{})["image"]; image != "mirror.example.com/app:1.0.0" {
+		t.Errorf("expected image to be overridden, got %v", image)
+	}
+	if tolerations, _, _ := unstructured.NestedSlice(objs[0].Object, "spec", "template", "spec", "tolerations"); len(tolerations) != 1 {
+		t.Errorf("expected tolerations to be patched, got %v", tolerations)
+	}
+}
@@ -0,0 +1,136 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+// RepoName returns the name of the HelmRepository object the chart of the addon is fetched from
+// HelmRepository objects are shared by all the addons using the same repository with the same credentials, so
+// the name is derived from the normalized URL of the repository and the references to its credentials.
+func RepoName(addon *v1alpha1.Addon) string {
+	chartSpec := addon.Spec.Chart
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", NormalizeRepoURL(chartSpec.Repo))
+	if chartSpec.SecretRef != nil {
+		fmt.Fprintf(h, "secret=%s\n", chartSpec.SecretRef.Name)
+	}
+	if chartSpec.CertSecretRef != nil {
+		fmt.Fprintf(h, "certSecret=%s\n", chartSpec.CertSecretRef.Name)
+	}
+	fmt.Fprintf(h, "passCredentials=%t\ninsecure=%t\n", chartSpec.PassCredentials, chartSpec.Insecure)
+
+	return fmt.Sprintf("repo-%x", h.Sum(nil)[:8])
+}
+
+// NormalizeRepoURL normalizes the URL of a chart repository, so that URLs only differing by the case of the
+// scheme and host or by a trailing slash refer to the same repository
+func NormalizeRepoURL(repo string) string {
+	repo = strings.TrimSpace(repo)
+	u, err := url.Parse(repo)
+	if err != nil || u.Host == "" {
+		return strings.TrimRight(repo, "/")
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+// acquireRepository records the addon as a user of the applied HelmRepository, with an owner reference
+// The owner references are the reference count of the shared repository. They also let the garbage collector
+// delete the repository if its last user is removed without releasing it.
+func (hc *Controller) acquireRepository(ctx context.Context, addon *v1alpha1.Addon, repo *sourcev1.HelmRepository) error {
+	if isRepositoryUser(repo, addon) {
+		return nil
+	}
+
+	// the optimistic lock prevents concurrent reconciliations from overwriting each other's references
+	patch := client.MergeFromWithOptions(repo.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if err := controllerutil.SetOwnerReference(addon, repo, hc.client.Scheme()); err != nil {
+		return fmt.Errorf("failed to set owner reference on helm repository %s: %w", repo.Name, err)
+	}
+	if err := hc.client.Patch(ctx, repo, patch); err != nil {
+		return fmt.Errorf("failed to add addon %s to the users of helm repository %s: %w", addon.Name, repo.Name, err)
+	}
+	return nil
+}
+
+// releaseRepositories removes the addon from the users of the HelmRepository objects it no longer uses, all of
+// them if keep is empty. Repositories without users left are deleted.
+func (hc *Controller) releaseRepositories(ctx context.Context, addon *v1alpha1.Addon, keep string) error {
+	repos := &sourcev1.HelmRepositoryList{}
+	if err := hc.client.List(ctx, repos, client.InNamespace(consts.NamespaceBlueprintSystem)); err != nil {
+		return fmt.Errorf("failed to list helm repositories: %w", err)
+	}
+
+	for i := range repos.Items {
+		repo := &repos.Items[i]
+		if repo.Name == keep || !isRepositoryUser(repo, addon) {
+			continue
+		}
+
+		if len(repo.OwnerReferences) == 1 {
+			hc.logger.Info("Deleting helm repository without users", "HelmRepo", repo.Name)
+			// the precondition prevents deleting a repository that has just been acquired by another addon
+			preconditions := metav1.Preconditions{UID: &repo.UID, ResourceVersion: &repo.ResourceVersion}
+			if err := hc.client.Delete(ctx, repo, client.Preconditions(preconditions)); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete helm repository %s: %w", repo.Name, err)
+			}
+			continue
+		}
+
+		hc.logger.Info("Releasing helm repository", "HelmRepo", repo.Name, "Addon", addon.Name)
+		patch := client.MergeFromWithOptions(repo.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		if err := controllerutil.RemoveOwnerReference(addon, repo, hc.client.Scheme()); err != nil {
+			return fmt.Errorf("failed to remove owner reference from helm repository %s: %w", repo.Name, err)
+		}
+		if err := hc.client.Patch(ctx, repo, patch); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to remove addon %s from the users of helm repository %s: %w", addon.Name, repo.Name, err)
+		}
+	}
+
+	// repositories created before they were shared are named after the addon and have no owner references
+	legacy := &sourcev1.HelmRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("repo-%s-%s", addon.Name, addon.Spec.Chart.Name),
+			Namespace: consts.NamespaceBlueprintSystem,
+		},
+	}
+	if err := hc.client.Get(ctx, client.ObjectKeyFromObject(legacy), legacy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get helm repository %s: %w", legacy.Name, err)
+	}
+	if len(legacy.OwnerReferences) > 0 {
+		return nil
+	}
+	hc.logger.Info("Deleting legacy helm repository", "HelmRepo", legacy.Name)
+	return hc.k8sClient.Delete(ctx, legacy)
+}
+
+// isRepositoryUser returns whether the addon is one of the users of the HelmRepository
+func isRepositoryUser(repo *sourcev1.HelmRepository, addon *v1alpha1.Addon) bool {
+	for _, ref := range repo.OwnerReferences {
+		if ref.UID == addon.UID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+	k8s "github.com/mirantiscontainers/blueprint-operator/pkg/kubernetes"
+)
+
+func TestNormalizeRepoURL(t *testing.T) {
+	tests := []struct {
+		repo     string
+		expected string
+	}{
+		{repo: "https://charts.bitnami.com/bitnami", expected: "https://charts.bitnami.com/bitnami"},
+		{repo: "HTTPS://Charts.Bitnami.com/bitnami/", expected: "https://charts.bitnami.com/bitnami"},
+		{repo: " https://charts.bitnami.com/bitnami// ", expected: "https://charts.bitnami.com/bitnami"},
+		{repo: "https://example.com/Charts", expected: "https://example.com/Charts"},
+		{repo: "oci://Registry.example.com/charts/", expected: "oci://registry.example.com/charts"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.repo, func(t *testing.T) {
+			if got := NormalizeRepoURL(tc.repo); got != tc.expected {
+				t.Errorf("NormalizeRepoURL() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRepoName(t *testing.T) {
+	addon := func(chart v1alpha1.ChartInfo) *v1alpha1.Addon {
+		return &v1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: chart.Name},
+			Spec:       v1alpha1.AddonSpec{Name: chart.Name, Chart: &chart},
+		}
+	}
+	nginx := RepoName(addon(v1alpha1.ChartInfo{Name: "nginx", Repo: "https://charts.bitnami.com/bitnami"}))
+
+	tests := []struct {
+		name   string
+		chart  v1alpha1.ChartInfo
+		shared bool
+	}{
+		{
+			name:   "other chart of the same repository",
+			chart:  v1alpha1.ChartInfo{Name: "redis", Repo: "https://charts.bitnami.com/bitnami"},
+			shared: true,
+		},
+		{
+			name:   "same repository with another URL case and a trailing slash",
+			chart:  v1alpha1.ChartInfo{Name: "redis", Repo: "https://Charts.Bitnami.com/bitnami/"},
+			shared: true,
+		},
+		{
+			name:  "other repository",
+			chart: v1alpha1.ChartInfo{Name: "redis", Repo: "https://charts.example.com/bitnami"},
+		},
+		{
+			name:  "same repository with credentials",
+			chart: v1alpha1.ChartInfo{Name: "redis", Repo: "https://charts.bitnami.com/bitnami", SecretRef: &v1alpha1.SecretReference{Name: "creds"}},
+		},
+		{
+			name:  "same repository with a CA",
+			chart: v1alpha1.ChartInfo{Name: "redis", Repo: "https://charts.bitnami.com/bitnami", CertSecretRef: &v1alpha1.SecretReference{Name: "ca"}},
+		},
+		{
+			name:  "same repository passing credentials",
+			chart: v1alpha1.ChartInfo{Name: "redis", Repo: "https://charts.bitnami.com/bitnami", PassCredentials: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := RepoName(addon(tc.chart))
+			if (got == nginx) != tc.shared {
+				t.Errorf("RepoName() = %q for nginx and %q for %s, expected shared: %t", nginx, got, tc.chart.Name, tc.shared)
+			}
+		})
+	}
+}
+
+func TestRepositoryReferences(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := sourcev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	addon := func(name string) *v1alpha1.Addon {
+		return &v1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: consts.NamespaceBlueprintSystem, UID: types.UID(name + "-uid")},
+			Spec: v1alpha1.AddonSpec{
+				Name:  name,
+				Kind:  "chart",
+				Chart: &v1alpha1.ChartInfo{Name: name, Repo: "https://charts.bitnami.com/bitnami", Version: "1.0.0"},
+			},
+		}
+	}
+	nginx, redis := addon("nginx"), addon("redis")
+	legacy := &sourcev1.HelmRepository{ObjectMeta: metav1.ObjectMeta{Name: "repo-nginx-nginx", Namespace: consts.NamespaceBlueprintSystem}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nginx, redis, legacy).Build()
+	hc := NewHelmChartController(c, k8s.NewClient(logr.Discard(), c), nil, logr.Discard())
+	ctx := context.TODO()
+
+	name := RepoName(nginx)
+	if RepoName(redis) != name {
+		t.Fatalf("expected the addons to share a repository")
+	}
+	for _, a := range []*v1alpha1.Addon{nginx, redis, nginx} {
+		repo := &sourcev1.HelmRepository{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: consts.NamespaceBlueprintSystem}}
+		if err := c.Create(ctx, repo); client.IgnoreAlreadyExists(err) != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(repo), repo); err != nil {
+			t.Fatalf("failed to get repository: %v", err)
+		}
+		if err := hc.acquireRepository(ctx, a, repo); err != nil {
+			t.Fatalf("failed to acquire repository for %s: %v", a.Name, err)
+		}
+		if err := hc.releaseRepositories(ctx, a, name); err != nil {
+			t.Fatalf("failed to release repositories for %s: %v", a.Name, err)
+		}
+	}
+
+	repo := &sourcev1.HelmRepository{}
+	key := types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: name}
+	if err := c.Get(ctx, key, repo); err != nil {
+		t.Fatalf("failed to get repository: %v", err)
+	}
+	if len(repo.OwnerReferences) != 2 {
+		t.Errorf("expected 2 users of the repository, got %d", len(repo.OwnerReferences))
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(legacy), legacy); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the legacy repository to be deleted, got %v", err)
+	}
+
+	if err := hc.releaseRepositories(ctx, nginx, ""); err != nil {
+		t.Fatalf("failed to release repositories for nginx: %v", err)
+	}
+	if err := c.Get(ctx, key, repo); err != nil {
+		t.Fatalf("expected the repository to be kept for redis, got %v", err)
+	}
+	if len(repo.OwnerReferences) != 1 || repo.OwnerReferences[0].UID != redis.UID {
+		t.Errorf("expected redis to be the only user of the repository, got %v", repo.OwnerReferences)
+	}
+
+	if err := hc.releaseRepositories(ctx, redis, ""); err != nil {
+		t.Fatalf("failed to release repositories for redis: %v", err)
+	}
+	if err := c.Get(ctx, key, repo); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the repository to be deleted with its last user, got %v", err)
+	}
+}
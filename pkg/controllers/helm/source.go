@@ -0,0 +1,60 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+const (
+	KindHelmRepository = "HelmRepository"
+	KindHelmChart      = "HelmChart"
+)
+
+// sourceObject is a Flux source reporting its state with conditions
+type sourceObject interface {
+	client.Object
+	GetConditions() []metav1.Condition
+}
+
+// HelmChartName returns the name of the HelmChart object generated by the helm-controller for the HelmRelease
+// of the addon, in the blueprint-system namespace
+func HelmChartName(addon *v1alpha1.Addon) string {
+	return fmt.Sprintf("%s-%s", consts.NamespaceBlueprintSystem, addon.Spec.Name)
+}
+
+// SourceFailure describes why the source of a chart addon isn't ready, from the Ready condition of its
+// HelmRepository or its HelmChart, e.g. when the repository index can't be fetched, the credentials are rejected
+// or the chart version doesn't exist. Either source can be nil if it doesn't exist (yet). It is empty if the
+// sources are ready, or if their conditions describe a previous generation of the sources.
+func SourceFailure(repo *sourcev1.HelmRepository, chart *sourcev1.HelmChart) string {
+	if repo != nil {
+		if failure := sourceFailure(KindHelmRepository, repo); failure != "" {
+			return failure
+		}
+	}
+	if chart != nil {
+		return sourceFailure(KindHelmChart, chart)
+	}
+	return ""
+}
+
+// sourceFailure describes why the source isn't ready, if its Ready condition is false for the current generation
+func sourceFailure(kind string, obj sourceObject) string {
+	for _, cond := range obj.GetConditions() {
+		if cond.Type != meta.ReadyCondition {
+			continue
+		}
+		if cond.Status != metav1.ConditionFalse || cond.ObservedGeneration < obj.GetGeneration() {
+			return ""
+		}
+		return fmt.Sprintf("%s %s: %s: %s", kind, obj.GetName(), cond.Reason, cond.Message)
+	}
+	return ""
+}
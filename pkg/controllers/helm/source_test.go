@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSourceFailure(t *testing.T) {
+	repo := func(generation int64, conditions ...metav1.Condition) *sourcev1.HelmRepository {
+		return &sourcev1.HelmRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "repo-a-b", Generation: generation},
+			Status:     sourcev1.HelmRepositoryStatus{Conditions: conditions},
+		}
+	}
+	chart := func(generation int64, conditions ...metav1.Condition) *sourcev1.HelmChart {
+		return &sourcev1.HelmChart{
+			ObjectMeta: metav1.ObjectMeta{Name: "blueprint-system-a", Generation: generation},
+			Status:     sourcev1.HelmChartStatus{Conditions: conditions},
+		}
+	}
+	ready := metav1.Condition{Type: meta.ReadyCondition, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: meta.SucceededReason}
+	fetchFailed := metav1.Condition{Type: meta.ReadyCondition, Status: metav1.ConditionFalse, ObservedGeneration: 1, Reason: sourcev1.FetchFailedCondition, Message: "failed to fetch index: 404"}
+	authFailed := metav1.Condition{Type: meta.ReadyCondition, Status: metav1.ConditionFalse, ObservedGeneration: 1, Reason: sourcev1.AuthenticationFailedReason, Message: "invalid credentials"}
+	chartNotFound := metav1.Condition{Type: meta.ReadyCondition, Status: metav1.ConditionFalse, ObservedGeneration: 1, Reason: "ChartPullError", Message: "no chart version found for a-1.0.0"}
+	reconciling := metav1.Condition{Type: meta.ReadyCondition, Status: metav1.ConditionUnknown, ObservedGeneration: 1, Reason: meta.ProgressingReason}
+
+	tests := []struct {
+		name     string
+		repo     *sourcev1.HelmRepository
+		chart    *sourcev1.HelmChart
+		expected string
+	}{
+		{
+			name: "no sources",
+		},
+		{
+			name:  "ready sources",
+			repo:  repo(1, ready),
+			chart: chart(1, ready),
+		},
+		{
+			name:  "reconciling sources",
+			repo:  repo(1, reconciling),
+			chart: chart(1, reconciling),
+		},
+		{
+			name:     "repository fetch failed",
+			repo:     repo(1, fetchFailed),
+			expected: "HelmRepository repo-a-b: FetchFailed: failed to fetch index: 404",
+		},
+		{
+			name:     "repository authentication failed",
+			repo:     repo(1, authFailed),
+			chart:    chart(1, chartNotFound),
+			expected: "HelmRepository repo-a-b: AuthenticationFailed: invalid credentials",
+		},
+		{
+			name:     "chart not found",
+			repo:     repo(1, ready),
+			chart:    chart(1, chartNotFound),
+			expected: "HelmChart blueprint-system-a: ChartPullError: no chart version found for a-1.0.0",
+		},
+		{
+			name:  "failure of a previous generation",
+			repo:  repo(2, fetchFailed),
+			chart: chart(2, chartNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SourceFailure(tt.repo, tt.chart); got != tt.expected {
+				t.Errorf("SourceFailure() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
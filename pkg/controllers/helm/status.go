@@ -1,9 +1,11 @@
 package helm
 
 import (
+	"fmt"
 	"slices"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +27,11 @@ func DetermineReleaseStatus(release *helmv2.HelmRelease) string {
 		helmv2.UninstallFailedReason,
 	}
 
+	// The conditions describe a previous generation of the release until helm-controller picks up the change
+	if release.Status.ObservedGeneration < release.Generation {
+		return ReleaseStatusProgressing
+	}
+
 	// Check if the release has a "Released" condition
 	// If the condition is true, the release was successful
 	// If the condition is false and the reason is InstallFailed, the release failed
@@ -42,3 +49,16 @@ func DetermineReleaseStatus(release *helmv2.HelmRelease) string {
 
 	return ReleaseStatusProgressing
 }
+
+// FailureReason describes why the release isn't ready with the reason and message of its Ready condition as set
+// by the helm-controller, falling back to its Released condition. It is empty if there is no such condition yet.
+func FailureReason(release *helmv2.HelmRelease) string {
+	for _, condType := range []string{meta.ReadyCondition, helmv2.ReleasedCondition} {
+		for _, cond := range release.Status.Conditions {
+			if cond.Type == condType && cond.Status != metav1.ConditionTrue && cond.Reason != "" {
+				return fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+			}
+		}
+	}
+	return ""
+}
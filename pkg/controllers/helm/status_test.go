@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -120,3 +121,45 @@ func TestDetermineReleaseStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		expected   string
+	}{
+		{
+			name: "no conditions",
+		},
+		{
+			name: "ready",
+			conditions: []metav1.Condition{
+				{Type: meta.ReadyCondition, Status: metav1.ConditionTrue, Reason: helmv2.InstallSucceededReason},
+			},
+		},
+		{
+			name: "not ready",
+			conditions: []metav1.Condition{
+				{Type: helmv2.ReleasedCondition, Status: metav1.ConditionFalse, Reason: helmv2.InstallFailedReason, Message: "install failed"},
+				{Type: meta.ReadyCondition, Status: metav1.ConditionFalse, Reason: helmv2.ArtifactFailedReason, Message: "chart not found"},
+			},
+			expected: "ArtifactFailed: chart not found",
+		},
+		{
+			name: "not released",
+			conditions: []metav1.Condition{
+				{Type: helmv2.ReleasedCondition, Status: metav1.ConditionFalse, Reason: helmv2.InstallFailedReason, Message: "install failed"},
+			},
+			expected: "InstallFailed: install failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &helmv2.HelmRelease{Status: helmv2.HelmReleaseStatus{Conditions: tt.conditions}}
+			if got := FailureReason(release); got != tt.expected {
+				t.Errorf("FailureReason() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
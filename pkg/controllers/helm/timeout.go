@@ -0,0 +1,159 @@
+package helm
+
+import (
+	"fmt"
+	"time"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+const (
+	FailurePolicyNone      = "None"
+	FailurePolicyRetry     = "Retry"
+	FailurePolicyUninstall = "Uninstall"
+
+	// defaultMaxRetries is the number of retries of the Retry failure policy when MaxRetries is not set
+	defaultMaxRetries = 3
+)
+
+// OperationAction is the action to take for the operation recorded in the status of a chart addon
+type OperationAction int
+
+const (
+	// OperationActionNone means there is no operation in progress
+	OperationActionNone OperationAction = iota
+	// OperationActionWait means the operation is in progress and its deadline has not been reached yet
+	OperationActionWait
+	// OperationActionComplete means the release became ready and the operation can be completed
+	OperationActionComplete
+	// OperationActionRetry means the operation timed out and the release should be reset and released again
+	OperationActionRetry
+	// OperationActionUninstall means the operation timed out and the release should be uninstalled
+	OperationActionUninstall
+	// OperationActionFail means the operation timed out and the failure policy leaves the release as is, or the
+	// retries of the Retry failure policy are exhausted
+	OperationActionFail
+)
+
+// NewOperation returns a new attempt of the install or upgrade of the chart of the addon started at now
+func NewOperation(addon *v1alpha1.Addon, attempts int32, now time.Time) (*v1alpha1.ChartOperation, error) {
+	d, err := time.ParseDuration(addon.Spec.Chart.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timeout %q: %w", addon.Spec.Chart.Timeout, err)
+	}
+
+	return &v1alpha1.ChartOperation{
+		Generation: addon.Generation,
+		Attempts:   attempts,
+		StartTime:  metav1.NewTime(now),
+		Deadline:   metav1.NewTime(now.Add(d)),
+	}, nil
+}
+
+// ValidateTimeout checks the timeout and failure policy of a chart addon
+func ValidateTimeout(chart *v1alpha1.ChartInfo) error {
+	switch chart.FailurePolicy {
+	case "", FailurePolicyNone, FailurePolicyRetry, FailurePolicyUninstall:
+	default:
+		return fmt.Errorf("unsupported failure policy %q", chart.FailurePolicy)
+	}
+	if chart.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative, got %d", chart.MaxRetries)
+	}
+	if chart.MaxRetries > 0 && chart.FailurePolicy != FailurePolicyRetry {
+		return fmt.Errorf("max retries is only supported with the %s failure policy", FailurePolicyRetry)
+	}
+
+	if chart.Timeout == "" {
+		return nil
+	}
+	if _, err := parseDuration("timeout", chart.Timeout); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CheckOperation determines the action to take for the operation recorded in the status of the chart addon,
+// given the status of its release as returned by DetermineReleaseStatus. For OperationActionWait, the returned
+// duration is the time left until the deadline of the operation.
+func CheckOperation(addon *v1alpha1.Addon, releaseStatus string, now time.Time) (OperationAction, time.Duration) {
+	op := addon.Status.Operation
+	if op == nil || op.CompletionTime != nil || op.Generation != addon.Generation {
+		return OperationActionNone, 0
+	}
+
+	if releaseStatus == ReleaseStatusSuccess {
+		return OperationActionComplete, 0
+	}
+
+	if remaining := op.Deadline.Sub(now); remaining > 0 {
+		return OperationActionWait, remaining
+	}
+
+	switch addon.Spec.Chart.FailurePolicy {
+	case FailurePolicyRetry:
+		// the first attempt is not a retry
+		if op.Attempts-1 >= maxRetries(addon.Spec.Chart) {
+			return OperationActionFail, 0
+		}
+		return OperationActionRetry, 0
+	case FailurePolicyUninstall:
+		return OperationActionUninstall, 0
+	}
+	return OperationActionFail, 0
+}
+
+// maxRetries returns the number of retries of the Retry failure policy of the chart
+func maxRetries(chart *v1alpha1.ChartInfo) int32 {
+	if chart.MaxRetries > 0 {
+		return chart.MaxRetries
+	}
+	return defaultMaxRetries
+}
+
+// OperationFailed returns whether the operation of the current generation of the addon timed out and
+// was completed without being retried
+func OperationFailed(addon *v1alpha1.Addon) bool {
+	op := addon.Status.Operation
+	if op == nil || op.Generation != addon.Generation || op.CompletionTime == nil || op.LastFailureTime == nil {
+		return false
+	}
+	return op.CompletionTime.Equal(op.LastFailureTime)
+}
+
+// OperationUninstalled returns whether the release of the current generation of the addon was uninstalled as
+// per the Uninstall failure policy. The release must not be installed again until the addon spec changes.
+func OperationUninstalled(addon *v1alpha1.Addon) bool {
+	op := addon.Status.Operation
+	return op != nil && op.Generation == addon.Generation && op.Uninstalled
+}
+
+// releaseAnnotations returns the annotations of the HelmRelease of the addon
+// The digest of the values referenced by the chart requests a reconciliation of the release whenever they change.
+// While the operation of the addon is being retried, the release is also reset and forced, so that the
+// helm-controller releases the chart again even though it ran out of retries. The same token must be used for
+// all the requests, as the helm-controller only handles a reset or force when it matches the reconcile request.
+func releaseAnnotations(addon *v1alpha1.Addon, valuesDigest string) map[string]string {
+	token := valuesDigest
+	if op := addon.Status.Operation; op != nil && op.Generation == addon.Generation && op.Attempts > 1 && op.CompletionTime == nil {
+		token = fmt.Sprintf("reset-%d-%d", op.Generation, op.Attempts)
+		if valuesDigest != "" {
+			token = fmt.Sprintf("%s-%s", valuesDigest, token)
+		}
+
+		return map[string]string{
+			meta.ReconcileRequestAnnotation: token,
+			helmv2.ResetRequestAnnotation:   token,
+			helmv2.ForceRequestAnnotation:   token,
+		}
+	}
+
+	if token == "" {
+		return nil
+	}
+	return map[string]string{meta.ReconcileRequestAnnotation: token}
+}
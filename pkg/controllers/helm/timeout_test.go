@@ -0,0 +1,301 @@
+package helm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+func TestCheckOperation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	addon := func(policy string, op *v1alpha1.ChartOperation) *v1alpha1.Addon {
+		a := &v1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: v1alpha1.AddonSpec{
+				Chart: &v1alpha1.ChartInfo{Timeout: "5m", FailurePolicy: policy},
+			},
+			Status: v1alpha1.AddonStatus{Operation: op},
+		}
+		return a
+	}
+	started := func(start time.Time) *v1alpha1.ChartOperation {
+		op, err := NewOperation(addon("", nil), 1, start)
+		if err != nil {
+			t.Fatalf("failed to create operation: %v", err)
+		}
+		return op
+	}
+	completed := started(now.Add(-10 * time.Minute))
+	completed.CompletionTime = &metav1.Time{Time: now.Add(-time.Minute)}
+	stale := started(now.Add(-10 * time.Minute))
+	stale.Generation = 1
+	retried := func(attempts int32) *v1alpha1.ChartOperation {
+		op := started(now.Add(-10 * time.Minute))
+		op.Attempts = attempts
+		return op
+	}
+	limited := addon(FailurePolicyRetry, retried(3))
+	limited.Spec.Chart.MaxRetries = 2
+
+	tests := []struct {
+		name              string
+		addon             *v1alpha1.Addon
+		releaseStatus     string
+		expectedAction    OperationAction
+		expectedRemaining time.Duration
+	}{
+		{
+			name:           "no operation",
+			addon:          addon(FailurePolicyRetry, nil),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionNone,
+		},
+		{
+			name:           "completed operation",
+			addon:          addon(FailurePolicyRetry, completed),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionNone,
+		},
+		{
+			name:           "operation of a previous generation",
+			addon:          addon(FailurePolicyRetry, stale),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionNone,
+		},
+		{
+			name:           "release ready",
+			addon:          addon(FailurePolicyRetry, started(now.Add(-10*time.Minute))),
+			releaseStatus:  ReleaseStatusSuccess,
+			expectedAction: OperationActionComplete,
+		},
+		{
+			name:              "before the deadline",
+			addon:             addon(FailurePolicyRetry, started(now.Add(-2*time.Minute))),
+			releaseStatus:     ReleaseStatusFailed,
+			expectedAction:    OperationActionWait,
+			expectedRemaining: 3 * time.Minute,
+		},
+		{
+			name:           "timed out without a policy",
+			addon:          addon("", started(now.Add(-10*time.Minute))),
+			releaseStatus:  ReleaseStatusProgressing,
+			expectedAction: OperationActionFail,
+		},
+		{
+			name:           "timed out with the None policy",
+			addon:          addon(FailurePolicyNone, started(now.Add(-10*time.Minute))),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionFail,
+		},
+		{
+			name:           "timed out with the Retry policy",
+			addon:          addon(FailurePolicyRetry, started(now.Add(-10*time.Minute))),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionRetry,
+		},
+		{
+			name:           "timed out with retries left",
+			addon:          addon(FailurePolicyRetry, retried(defaultMaxRetries)),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionRetry,
+		},
+		{
+			name:           "timed out with the default retries exhausted",
+			addon:          addon(FailurePolicyRetry, retried(defaultMaxRetries+1)),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionFail,
+		},
+		{
+			name:           "timed out with the max retries exhausted",
+			addon:          limited,
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionFail,
+		},
+		{
+			name:           "timed out with the Uninstall policy",
+			addon:          addon(FailurePolicyUninstall, started(now.Add(-10*time.Minute))),
+			releaseStatus:  ReleaseStatusFailed,
+			expectedAction: OperationActionUninstall,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			action, remaining := CheckOperation(tc.addon, tc.releaseStatus, now)
+			if action != tc.expectedAction {
+				t.Errorf("expected action %d, got %d", tc.expectedAction, action)
+			}
+			if remaining != tc.expectedRemaining {
+				t.Errorf("expected remaining %s, got %s", tc.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestOperationFailed(t *testing.T) {
+	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	failureTime := metav1.NewTime(completionTime.Add(-5 * time.Minute))
+
+	tests := []struct {
+		name     string
+		op       *v1alpha1.ChartOperation
+		expected bool
+	}{
+		{
+			name: "no operation",
+		},
+		{
+			name: "in progress",
+			op:   &v1alpha1.ChartOperation{Generation: 1, LastFailure: "timed out", LastFailureTime: &failureTime},
+		},
+		{
+			name: "completed after a retry",
+			op:   &v1alpha1.ChartOperation{Generation: 1, LastFailure: "timed out", LastFailureTime: &failureTime, CompletionTime: &completionTime},
+		},
+		{
+			name: "previous generation",
+			op:   &v1alpha1.ChartOperation{Generation: 0, LastFailure: "timed out", LastFailureTime: &completionTime, CompletionTime: &completionTime},
+		},
+		{
+			name:     "timed out",
+			op:       &v1alpha1.ChartOperation{Generation: 1, LastFailure: "timed out", LastFailureTime: &completionTime, CompletionTime: &completionTime},
+			expected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			addon := &v1alpha1.Addon{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Status:     v1alpha1.AddonStatus{Operation: tc.op},
+			}
+			if got := OperationFailed(addon); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestReleaseAnnotations(t *testing.T) {
+	completionTime := metav1.Now()
+
+	tests := []struct {
+		name         string
+		op           *v1alpha1.ChartOperation
+		valuesDigest string
+		expected     map[string]string
+	}{
+		{
+			name: "no operation nor values",
+		},
+		{
+			name:         "values digest",
+			valuesDigest: "sha256:abc",
+			expected:     map[string]string{meta.ReconcileRequestAnnotation: "sha256:abc"},
+		},
+		{
+			name: "first attempt",
+			op:   &v1alpha1.ChartOperation{Generation: 3, Attempts: 1},
+		},
+		{
+			name: "retried attempt",
+			op:   &v1alpha1.ChartOperation{Generation: 3, Attempts: 2},
+			expected: map[string]string{
+				meta.ReconcileRequestAnnotation: "reset-3-2",
+				helmv2.ResetRequestAnnotation:   "reset-3-2",
+				helmv2.ForceRequestAnnotation:   "reset-3-2",
+			},
+		},
+		{
+			name:         "retried attempt with values digest",
+			op:           &v1alpha1.ChartOperation{Generation: 3, Attempts: 2},
+			valuesDigest: "sha256:abc",
+			expected: map[string]string{
+				meta.ReconcileRequestAnnotation: "sha256:abc-reset-3-2",
+				helmv2.ResetRequestAnnotation:   "sha256:abc-reset-3-2",
+				helmv2.ForceRequestAnnotation:   "sha256:abc-reset-3-2",
+			},
+		},
+		{
+			name:         "completed retried attempt",
+			op:           &v1alpha1.ChartOperation{Generation: 3, Attempts: 2, CompletionTime: &completionTime},
+			valuesDigest: "sha256:abc",
+			expected:     map[string]string{meta.ReconcileRequestAnnotation: "sha256:abc"},
+		},
+		{
+			name: "retried attempt of a previous generation",
+			op:   &v1alpha1.ChartOperation{Generation: 2, Attempts: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			addon := &v1alpha1.Addon{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Status:     v1alpha1.AddonStatus{Operation: tc.op},
+			}
+			if got := releaseAnnotations(addon, tc.valuesDigest); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		chart       *v1alpha1.ChartInfo
+		expectError bool
+	}{
+		{
+			name:  "no timeout",
+			chart: &v1alpha1.ChartInfo{},
+		},
+		{
+			name:  "timeout and policy",
+			chart: &v1alpha1.ChartInfo{Timeout: "10m", FailurePolicy: FailurePolicyUninstall},
+		},
+		{
+			name:        "invalid timeout",
+			chart:       &v1alpha1.ChartInfo{Timeout: "10 minutes"},
+			expectError: true,
+		},
+		{
+			name:  "max retries",
+			chart: &v1alpha1.ChartInfo{Timeout: "10m", FailurePolicy: FailurePolicyRetry, MaxRetries: 5},
+		},
+		{
+			name:        "negative max retries",
+			chart:       &v1alpha1.ChartInfo{Timeout: "10m", FailurePolicy: FailurePolicyRetry, MaxRetries: -1},
+			expectError: true,
+		},
+		{
+			name:        "max retries without the Retry policy",
+			chart:       &v1alpha1.ChartInfo{Timeout: "10m", FailurePolicy: FailurePolicyNone, MaxRetries: 5},
+			expectError: true,
+		},
+		{
+			name:        "invalid policy",
+			chart:       &v1alpha1.ChartInfo{Timeout: "10m", FailurePolicy: "Rollback"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTimeout(tc.chart)
+			if tc.expectError && err == nil {
+				t.Errorf("expected an error")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// maxSetIndex limits the list indices in set paths, as with Helm
+const maxSetIndex = 65536
+
+// setPathElement is a single element of a set path, either a map key or a list index
+type setPathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// ValidateSetPath returns an error if the path of a set entry can't be parsed
+func ValidateSetPath(path string) error {
+	_, err := parseSetPath(path)
+	return err
+}
+
+// parseSetPath parses a Helm --set style path such as "a.b[0].c" or "annotations.example\.com/name"
+// Dots and brackets that are part of a key must be escaped with a backslash.
+func parseSetPath(path string) ([]setPathElement, error) {
+	var (
+		elements []setPathElement
+		key      strings.Builder
+		// afterIndex is true right after a closing bracket, where only '.', '[' or the end of the path may follow
+		afterIndex bool
+	)
+
+	runes := []rune(path)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if afterIndex && r != '.' && r != '[' {
+			return nil, fmt.Errorf("unexpected %q after list index at position %d", r, i)
+		}
+
+		switch r {
+		case '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("path ends with an escape character")
+			}
+			i++
+			key.WriteRune(runes[i])
+		case '.':
+			if !afterIndex {
+				if key.Len() == 0 {
+					return nil, fmt.Errorf("empty key at position %d", i)
+				}
+				elements = append(elements, setPathElement{key: key.String()})
+				key.Reset()
+			}
+			afterIndex = false
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("path ends with a dot")
+			}
+		case '[':
+			if !afterIndex {
+				if key.Len() == 0 {
+					return nil, fmt.Errorf("list index without a key at position %d", i)
+				}
+				elements = append(elements, setPathElement{key: key.String()})
+				key.Reset()
+			}
+
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated list index at position %d", i)
+			}
+
+			index, err := strconv.Atoi(string(runes[i+1 : end]))
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid list index %q at position %d", string(runes[i+1:end]), i)
+			}
+			if index > maxSetIndex {
+				return nil, fmt.Errorf("list index %d exceeds the maximum of %d", index, maxSetIndex)
+			}
+			elements = append(elements, setPathElement{index: index, isIndex: true})
+			i = end
+			afterIndex = true
+		case ']':
+			return nil, fmt.Errorf("unexpected ']' at position %d", i)
+		default:
+			key.WriteRune(r)
+		}
+	}
+
+	if !afterIndex {
+		if key.Len() == 0 {
+			return nil, fmt.Errorf("empty key")
+		}
+		elements = append(elements, setPathElement{key: key.String()})
+	}
+
+	return elements, nil
+}
+
+// MergeSetValues merges the set entries of a chart addon into its values
+// As with Helm, set entries take precedence over values. Entries are applied in lexical order of their paths.
+func MergeSetValues(values *apiextensionsv1.JSON, set map[string]intstr.IntOrString) (*apiextensionsv1.JSON, error) {
+	if len(set) == 0 {
+		return values, nil
+	}
+
+	merged := map[string]interface{}{}
+	if values != nil && len(values.Raw) > 0 {
+		if err := json.Unmarshal(values.Raw, &merged); err != nil {
+			return nil, fmt.Errorf("failed to parse values: %w", err)
+		}
+		if merged == nil {
+			merged = map[string]interface{}{}
+		}
+	}
+
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		elements, err := parseSetPath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid set path %q: %w", p, err)
+		}
+		merged = setValue(merged, elements, parseSetValue(set[p])).(map[string]interface{})
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode values: %w", err)
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// setValue sets the value at the path in the node, creating intermediate maps and lists as needed
+// Existing nodes of a different type are replaced, and lists are padded with nil up to the index.
+func setValue(node interface{}, path []setPathElement, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+
+	element := path[0]
+	if element.isIndex {
+		list, _ := node.([]interface{})
+		for len(list) <= element.index {
+			list = append(list, nil)
+		}
+		list[element.index] = setValue(list[element.index], path[1:], value)
+		return list
+	}
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+	}
+	m[element.key] = setValue(m[element.key], path[1:], value)
+	return m
+}
+
+// parseSetValue converts a set value the way `helm --set` does
+// Integers are kept, and strings are converted to booleans, integers, null or a list of values
+// written as {a,b,c} where possible.
+func parseSetValue(v intstr.IntOrString) interface{} {
+	if v.Type == intstr.Int {
+		return int64(v.IntVal)
+	}
+	return typedValue(v.StrVal)
+}
+
+func typedValue(s string) interface{} {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		var list []interface{}
+		for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}"), ",") {
+			if item != "" {
+				list = append(list, typedValue(item))
+			}
+		}
+		return list
+	}
+
+	// leading zeros are kept as strings, as with Helm
+	if s == "0" || (!strings.HasPrefix(s, "0") && !strings.HasPrefix(s, "-0")) {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	}
+	return s
+}
+
+// mergeValues merges src into dst recursively, values in src take precedence
+// A nil value in src removes the key from dst, as with Helm.
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[k] = mergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// deepCopyValues copies the nested maps and lists of the values, scalars are shared
+func deepCopyValues(values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+func deepCopyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return deepCopyValues(t)
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			out[i] = deepCopyValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
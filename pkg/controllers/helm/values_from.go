@@ -0,0 +1,99 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+const (
+	kindConfigMap = "ConfigMap"
+	kindSecret    = "Secret"
+)
+
+// ResolveValuesFrom reads the values referenced by the chart from ConfigMaps and Secrets of the blueprint-system
+// namespace and merges them in order, the same way the helm-controller does. It also returns a digest of the
+// referenced data, which changes whenever any of the referenced keys changes.
+// Missing objects or keys are ignored for optional references only.
+func ResolveValuesFrom(ctx context.Context, c client.Reader, refs []v1alpha1.ValuesReference) (map[string]interface{}, string, error) {
+	values := map[string]interface{}{}
+	if len(refs) == 0 {
+		return values, "", nil
+	}
+
+	h := sha256.New()
+	for _, ref := range refs {
+		data, found, err := getValuesData(ctx, c, ref)
+		if err != nil {
+			return nil, "", err
+		}
+		if !found {
+			if ref.Optional {
+				continue
+			}
+			return nil, "", fmt.Errorf("key %s not found in %s %s", ref.GetValuesKey(), ref.Kind, ref.Name)
+		}
+		fmt.Fprintf(h, "%s/%s/%s/%s=%d:", ref.Kind, ref.Name, ref.GetValuesKey(), ref.TargetPath, len(data))
+		h.Write(data)
+
+		if ref.TargetPath != "" {
+			elements, err := parseSetPath(ref.TargetPath)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid target path %q for %s %s: %w", ref.TargetPath, ref.Kind, ref.Name, err)
+			}
+			values = setValue(values, elements, string(data)).(map[string]interface{})
+			continue
+		}
+
+		refValues := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &refValues); err != nil {
+			return nil, "", fmt.Errorf("failed to parse values from key %s of %s %s: %w", ref.GetValuesKey(), ref.Kind, ref.Name, err)
+		}
+		values = mergeValues(values, refValues)
+	}
+
+	return values, fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// getValuesData returns the data of the key of the referenced ConfigMap or Secret and whether it was found
+func getValuesData(ctx context.Context, c client.Reader, ref v1alpha1.ValuesReference) ([]byte, bool, error) {
+	key := types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: ref.Name}
+
+	switch ref.Kind {
+	case kindConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, key, cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("failed to get ConfigMap %s: %w", ref.Name, err)
+		}
+		if data, ok := cm.Data[ref.GetValuesKey()]; ok {
+			return []byte(data), true, nil
+		}
+		data, ok := cm.BinaryData[ref.GetValuesKey()]
+		return data, ok, nil
+
+	case kindSecret:
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, key, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("failed to get Secret %s: %w", ref.Name, err)
+		}
+		data, ok := secret.Data[ref.GetValuesKey()]
+		return data, ok, nil
+	}
+
+	return nil, false, fmt.Errorf("unsupported values reference kind %q", ref.Kind)
+}
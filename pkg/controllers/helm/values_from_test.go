@@ -0,0 +1,128 @@
+package helm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+func TestResolveValuesFrom(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: consts.NamespaceBlueprintSystem},
+			Data: map[string]string{
+				"values.yaml": "replicas: 2\nimage:\n  tag: \"1.0\"\n  pullPolicy: Always\n",
+				"prod.yaml":   "replicas: 3\n",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: consts.NamespaceBlueprintSystem},
+			Data: map[string][]byte{
+				"values.yaml": []byte("image:\n  tag: \"2.0\"\n"),
+				"password":    []byte("s3cr3t"),
+			},
+		},
+	).Build()
+
+	tests := []struct {
+		name          string
+		refs          []v1alpha1.ValuesReference
+		expected      map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:     "no references",
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "merged in order",
+			refs: []v1alpha1.ValuesReference{
+				{Kind: "ConfigMap", Name: "defaults"},
+				{Kind: "ConfigMap", Name: "defaults", ValuesKey: "prod.yaml"},
+				{Kind: "Secret", Name: "credentials"},
+			},
+			expected: map[string]interface{}{
+				"replicas": float64(3),
+				"image":    map[string]interface{}{"tag": "2.0", "pullPolicy": "Always"},
+			},
+		},
+		{
+			name: "target path",
+			refs: []v1alpha1.ValuesReference{
+				{Kind: "ConfigMap", Name: "defaults"},
+				{Kind: "Secret", Name: "credentials", ValuesKey: "password", TargetPath: "auth.password"},
+			},
+			expected: map[string]interface{}{
+				"replicas": float64(2),
+				"image":    map[string]interface{}{"tag": "1.0", "pullPolicy": "Always"},
+				"auth":     map[string]interface{}{"password": "s3cr3t"},
+			},
+		},
+		{
+			name:     "missing optional key",
+			refs:     []v1alpha1.ValuesReference{{Kind: "ConfigMap", Name: "defaults", ValuesKey: "dev.yaml", Optional: true}},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:          "missing object",
+			refs:          []v1alpha1.ValuesReference{{Kind: "Secret", Name: "missing"}},
+			expectedError: "key values.yaml not found in Secret missing",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			values, digest, err := ResolveValuesFrom(context.TODO(), c, tc.refs)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(values, tc.expected) {
+				t.Errorf("expected values %v, got %v", tc.expected, values)
+			}
+			if (digest == "") != (len(tc.refs) == 0) {
+				t.Errorf("unexpected digest %q for %d references", digest, len(tc.refs))
+			}
+		})
+	}
+}
+
+func TestResolveValuesFromDigest(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: consts.NamespaceBlueprintSystem},
+		Data:       map[string]string{"values.yaml": "replicas: 2\n"},
+	}
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+	refs := []v1alpha1.ValuesReference{{Kind: "ConfigMap", Name: "defaults"}}
+
+	_, before, err := ResolveValuesFrom(context.TODO(), c, refs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cm.Data["values.yaml"] = "replicas: 3\n"
+	if err = c.Update(context.TODO(), cm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, after, err := ResolveValuesFrom(context.TODO(), c, refs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before == after {
+		t.Errorf("expected the digest to change when the referenced values change, got %q", after)
+	}
+}
@@ -0,0 +1,118 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestMergeSetValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   string
+		set      map[string]intstr.IntOrString
+		expected string
+	}{
+		{
+			name:     "no set entries",
+			values:   `{"a":1}`,
+			expected: `{"a":1}`,
+		},
+		{
+			name:     "nested keys without values",
+			set:      map[string]intstr.IntOrString{"a.b.c": intstr.FromString("d")},
+			expected: `{"a":{"b":{"c":"d"}}}`,
+		},
+		{
+			name:     "set takes precedence over values",
+			values:   `{"image":{"repository":"nginx","tag":"1.25"}}`,
+			set:      map[string]intstr.IntOrString{"image.tag": intstr.FromString("1.26")},
+			expected: `{"image":{"repository":"nginx","tag":"1.26"}}`,
+		},
+		{
+			name: "typed values",
+			set: map[string]intstr.IntOrString{
+				"replicas": intstr.FromInt32(3),
+				"port":     intstr.FromString("8080"),
+				"enabled":  intstr.FromString("true"),
+				"version":  intstr.FromString("01"),
+				"removed":  intstr.FromString("null"),
+				"list":     intstr.FromString("{a,2}"),
+			},
+			expected: `{"enabled":true,"list":["a",2],"port":8080,"removed":null,"replicas":3,"version":"01"}`,
+		},
+		{
+			name:     "list indices",
+			values:   `{"servers":[{"name":"a","port":80}]}`,
+			set:      map[string]intstr.IntOrString{"servers[0].port": intstr.FromInt32(81), "servers[2].name": intstr.FromString("c")},
+			expected: `{"servers":[{"name":"a","port":81},null,{"name":"c"}]}`,
+		},
+		{
+			name:     "nested list indices",
+			set:      map[string]intstr.IntOrString{"matrix[1][0]": intstr.FromInt32(1)},
+			expected: `{"matrix":[null,[1]]}`,
+		},
+		{
+			name:     "escaped dots",
+			set:      map[string]intstr.IntOrString{`podAnnotations.example\.com/name`: intstr.FromString("x")},
+			expected: `{"podAnnotations":{"example.com/name":"x"}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var values *apiextensionsv1.JSON
+			if tc.values != "" {
+				values = &apiextensionsv1.JSON{Raw: []byte(tc.values)}
+			}
+
+			actual, err := MergeSetValues(values, tc.set)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(actual.Raw) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, string(actual.Raw))
+			}
+		})
+	}
+}
+
+func TestValidateSetPath(t *testing.T) {
+	tests := []struct {
+		path          string
+		expectedError string
+	}{
+		{path: "a.b.c"},
+		{path: "a[0].b[1][2]"},
+		{path: `a\.b\[c\]`},
+		{path: "", expectedError: "empty key"},
+		{path: "a..b", expectedError: "empty key"},
+		{path: ".a", expectedError: "empty key"},
+		{path: "a.", expectedError: "ends with a dot"},
+		{path: `a\`, expectedError: "escape character"},
+		{path: "[0]", expectedError: "without a key"},
+		{path: "a[0", expectedError: "unterminated"},
+		{path: "a[x]", expectedError: "invalid list index"},
+		{path: "a[-1]", expectedError: "invalid list index"},
+		{path: "a[0]b", expectedError: "after list index"},
+		{path: "a]", expectedError: "unexpected ']'"},
+		{path: "a[65537]", expectedError: "exceeds the maximum"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			err := ValidateSetPath(tc.path)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
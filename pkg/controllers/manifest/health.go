@@ -0,0 +1,293 @@
+package manifest
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+// ObjectHealth computes the health of an object following the kstatus conventions
+// Generic rules are applied first: objects being deleted are Terminating, and objects whose status was not yet
+// observed for the latest generation, or with a Reconciling condition, are InProgress, and objects with a Stalled
+// condition are Failed. Kind specific rules are then applied for the built-in kinds with well-known status fields.
+// Objects without any of these are Current.
+func ObjectHealth(obj *unstructured.Unstructured) (v1alpha1.HealthStatus, string, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return v1alpha1.HealthTerminating, "object is being deleted", nil
+	}
+
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err == nil && found && observedGeneration < obj.GetGeneration() {
+		return v1alpha1.HealthInProgress, fmt.Sprintf("observed generation %d is older than generation %d", observedGeneration, obj.GetGeneration()), nil
+	}
+
+	if c := findCondition(obj, "Stalled"); c != nil && c.status == "True" {
+		return v1alpha1.HealthFailed, c.describe(), nil
+	}
+	if c := findCondition(obj, "Reconciling"); c != nil && c.status == "True" {
+		return v1alpha1.HealthInProgress, c.describe(), nil
+	}
+
+	switch obj.GroupVersionKind().GroupKind().String() {
+	case "Deployment.apps":
+		return typedHealth(obj, &appsv1.Deployment{}, deploymentHealth)
+	case "StatefulSet.apps":
+		return typedHealth(obj, &appsv1.StatefulSet{}, statefulSetHealth)
+	case "DaemonSet.apps":
+		return typedHealth(obj, &appsv1.DaemonSet{}, daemonSetHealth)
+	case "ReplicaSet.apps":
+		return typedHealth(obj, &appsv1.ReplicaSet{}, replicaSetHealth)
+	case "Job.batch":
+		return typedHealth(obj, &batchv1.Job{}, jobHealth)
+	case "Pod":
+		return typedHealth(obj, &corev1.Pod{}, podHealth)
+	case "PersistentVolumeClaim":
+		return typedHealth(obj, &corev1.PersistentVolumeClaim{}, pvcHealth)
+	case "Service":
+		return typedHealth(obj, &corev1.Service{}, serviceHealth)
+	case "PodDisruptionBudget.policy":
+		return typedHealth(obj, &policyv1.PodDisruptionBudget{}, pdbHealth)
+	case "CustomResourceDefinition.apiextensions.k8s.io":
+		return crdHealth(obj)
+	case "APIService.apiregistration.k8s.io":
+		return apiServiceHealth(obj)
+	}
+
+	// custom resources that report readiness through a Ready condition
+	if c := findCondition(obj, "Ready"); c != nil && c.status == "False" {
+		return v1alpha1.HealthInProgress, c.describe(), nil
+	}
+
+	return v1alpha1.HealthCurrent, "", nil
+}
+
+// typedHealth converts the object to its typed form and computes its health with the provided function
+func typedHealth[T any](obj *unstructured.Unstructured, typed *T, health func(*T) (v1alpha1.HealthStatus, string)) (v1alpha1.HealthStatus, string, error) {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return "", "", fmt.Errorf("failed to convert %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	status, message := health(typed)
+	return status, message, nil
+}
+
+func deploymentHealth(d *appsv1.Deployment) (v1alpha1.HealthStatus, string) {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return v1alpha1.HealthFailed, fmt.Sprintf("progress deadline exceeded: %s", c.Message)
+		}
+	}
+
+	replicas := replicasOrDefault(d.Spec.Replicas)
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("updated replicas: %d/%d", d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("pending termination: %d", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("available replicas: %d/%d", d.Status.AvailableReplicas, replicas)
+	case d.Status.ReadyReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("ready replicas: %d/%d", d.Status.ReadyReplicas, replicas)
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable && c.Status == corev1.ConditionFalse {
+			return v1alpha1.HealthInProgress, fmt.Sprintf("deployment not available: %s", c.Message)
+		}
+	}
+
+	return v1alpha1.HealthCurrent, ""
+}
+
+func statefulSetHealth(s *appsv1.StatefulSet) (v1alpha1.HealthStatus, string) {
+	replicas := replicasOrDefault(s.Spec.Replicas)
+	switch {
+	case s.Status.Replicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("replicas: %d/%d", s.Status.Replicas, replicas)
+	case s.Status.ReadyReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("ready replicas: %d/%d", s.Status.ReadyReplicas, replicas)
+	case s.Status.AvailableReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("available replicas: %d/%d", s.Status.AvailableReplicas, replicas)
+	}
+
+	if s.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		// pods are only updated when they are deleted, so the revisions never converge on their own
+		return v1alpha1.HealthCurrent, ""
+	}
+
+	if rolling := s.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil && *rolling.Partition > 0 {
+		if s.Status.UpdatedReplicas < replicas-*rolling.Partition {
+			return v1alpha1.HealthInProgress, fmt.Sprintf("partitioned rollout: %d/%d updated", s.Status.UpdatedReplicas, replicas-*rolling.Partition)
+		}
+		return v1alpha1.HealthCurrent, ""
+	}
+
+	if s.Status.UpdateRevision != "" && s.Status.CurrentRevision != s.Status.UpdateRevision {
+		return v1alpha1.HealthInProgress, fmt.Sprintf("rolling update in progress: %d/%d updated", s.Status.UpdatedReplicas, replicas)
+	}
+
+	return v1alpha1.HealthCurrent, ""
+}
+
+func daemonSetHealth(d *appsv1.DaemonSet) (v1alpha1.HealthStatus, string) {
+	desired := d.Status.DesiredNumberScheduled
+	switch {
+	case d.Status.CurrentNumberScheduled < desired:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("scheduled pods: %d/%d", d.Status.CurrentNumberScheduled, desired)
+	case d.Status.UpdatedNumberScheduled < desired:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("updated pods: %d/%d", d.Status.UpdatedNumberScheduled, desired)
+	case d.Status.NumberAvailable < desired:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("available pods: %d/%d", d.Status.NumberAvailable, desired)
+	case d.Status.NumberReady < desired:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("ready pods: %d/%d", d.Status.NumberReady, desired)
+	}
+
+	return v1alpha1.HealthCurrent, ""
+}
+
+func replicaSetHealth(r *appsv1.ReplicaSet) (v1alpha1.HealthStatus, string) {
+	for _, c := range r.Status.Conditions {
+		if c.Type == appsv1.ReplicaSetReplicaFailure && c.Status == corev1.ConditionTrue {
+			return v1alpha1.HealthFailed, fmt.Sprintf("replica failure: %s", c.Message)
+		}
+	}
+
+	replicas := replicasOrDefault(r.Spec.Replicas)
+	switch {
+	case r.Status.FullyLabeledReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("labelled replicas: %d/%d", r.Status.FullyLabeledReplicas, replicas)
+	case r.Status.AvailableReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("available replicas: %d/%d", r.Status.AvailableReplicas, replicas)
+	case r.Status.ReadyReplicas < replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("ready replicas: %d/%d", r.Status.ReadyReplicas, replicas)
+	case r.Status.Replicas > replicas:
+		return v1alpha1.HealthInProgress, fmt.Sprintf("pending termination: %d", r.Status.Replicas-replicas)
+	}
+
+	return v1alpha1.HealthCurrent, ""
+}
+
+func jobHealth(j *batchv1.Job) (v1alpha1.HealthStatus, string) {
+	for _, c := range j.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return v1alpha1.HealthCurrent, ""
+		case batchv1.JobFailed:
+			return v1alpha1.HealthFailed, fmt.Sprintf("job failed: %s", c.Message)
+		}
+	}
+
+	return v1alpha1.HealthInProgress, fmt.Sprintf("job in progress: %d active, %d succeeded, %d failed", j.Status.Active, j.Status.Succeeded, j.Status.Failed)
+}
+
+func podHealth(p *corev1.Pod) (v1alpha1.HealthStatus, string) {
+	switch p.Status.Phase {
+	case corev1.PodSucceeded:
+		return v1alpha1.HealthCurrent, ""
+	case corev1.PodFailed:
+		return v1alpha1.HealthFailed, fmt.Sprintf("pod failed: %s", p.Status.Message)
+	}
+
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			return v1alpha1.HealthFailed, fmt.Sprintf("container %s is in CrashLoopBackOff", cs.Name)
+		}
+	}
+
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return v1alpha1.HealthCurrent, ""
+		}
+	}
+
+	return v1alpha1.HealthInProgress, fmt.Sprintf("pod is %s and not ready", p.Status.Phase)
+}
+
+func pvcHealth(p *corev1.PersistentVolumeClaim) (v1alpha1.HealthStatus, string) {
+	if p.Status.Phase != corev1.ClaimBound {
+		return v1alpha1.HealthInProgress, fmt.Sprintf("claim is %s", p.Status.Phase)
+	}
+	return v1alpha1.HealthCurrent, ""
+}
+
+func serviceHealth(s *corev1.Service) (v1alpha1.HealthStatus, string) {
+	if s.Spec.Type == corev1.ServiceTypeLoadBalancer && len(s.Status.LoadBalancer.Ingress) == 0 {
+		return v1alpha1.HealthInProgress, "load balancer ingress not yet assigned"
+	}
+	return v1alpha1.HealthCurrent, ""
+}
+
+func pdbHealth(p *policyv1.PodDisruptionBudget) (v1alpha1.HealthStatus, string) {
+	if p.Status.CurrentHealthy < p.Status.DesiredHealthy {
+		return v1alpha1.HealthInProgress, fmt.Sprintf("healthy pods: %d/%d", p.Status.CurrentHealthy, p.Status.DesiredHealthy)
+	}
+	return v1alpha1.HealthCurrent, ""
+}
+
+func crdHealth(obj *unstructured.Unstructured) (v1alpha1.HealthStatus, string, error) {
+	if c := findCondition(obj, "NamesAccepted"); c != nil && c.status == "False" {
+		return v1alpha1.HealthFailed, c.describe(), nil
+	}
+	if c := findCondition(obj, "Established"); c != nil && c.status == "True" {
+		return v1alpha1.HealthCurrent, "", nil
+	}
+	return v1alpha1.HealthInProgress, "CRD is not established", nil
+}
+
+func apiServiceHealth(obj *unstructured.Unstructured) (v1alpha1.HealthStatus, string, error) {
+	c := findCondition(obj, "Available")
+	if c != nil && c.status == "True" {
+		return v1alpha1.HealthCurrent, "", nil
+	}
+	if c != nil {
+		return v1alpha1.HealthInProgress, c.describe(), nil
+	}
+	return v1alpha1.HealthInProgress, "APIService is not available", nil
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// condition is a status condition read from an unstructured object
+type condition struct {
+	conditionType string
+	status        string
+	reason        string
+	message       string
+}
+
+func (c *condition) describe() string {
+	if c.message == "" {
+		return fmt.Sprintf("%s=%s: %s", c.conditionType, c.status, c.reason)
+	}
+	return fmt.Sprintf("%s=%s: %s: %s", c.conditionType, c.status, c.reason, c.message)
+}
+
+// findCondition returns the status condition of the provided type of an unstructured object, if any
+func findCondition(obj *unstructured.Unstructured, conditionType string) *condition {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok || m["type"] != conditionType {
+			continue
+		}
+		status, _ := m["status"].(string)
+		reason, _ := m["reason"].(string)
+		message, _ := m["message"].(string)
+		return &condition{conditionType: conditionType, status: status, reason: reason, message: message}
+	}
+	return nil
+}
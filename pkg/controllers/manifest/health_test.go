@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+var _ = Describe("Health", func() {
+	toUnstructured := func(manifest string) *unstructured.Unstructured {
+		data, err := yaml.YAMLToJSON([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		obj := &unstructured.Unstructured{}
+		Expect(obj.UnmarshalJSON(data)).To(Succeed())
+		return obj
+	}
+
+	DescribeTable("ObjectHealth",
+		func(manifest string, expected v1alpha1.HealthStatus) {
+			health, _, err := ObjectHealth(toUnstructured(manifest))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(health).To(Equal(expected))
+		},
+		Entry("object being deleted", `
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: test, deletionTimestamp: "2024-01-01T00:00:00Z"}
+`, v1alpha1.HealthTerminating),
+		Entry("object without status", `
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: test}
+`, v1alpha1.HealthCurrent),
+		Entry("stale observed generation", `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: test, generation: 2}
+spec: {replicas: 1}
+status: {observedGeneration: 1, replicas: 1, updatedReplicas: 1, readyReplicas: 1, availableReplicas: 1}
+`, v1alpha1.HealthInProgress),
+		Entry("stalled custom resource", `
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: test}
+status: {conditions: [{type: Stalled, status: "True", reason: Broken}]}
+`, v1alpha1.HealthFailed),
+		Entry("custom resource not ready", `
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: test}
+status: {conditions: [{type: Ready, status: "False", reason: Waiting}]}
+`, v1alpha1.HealthInProgress),
+		Entry("ready deployment", `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: test, generation: 1}
+spec: {replicas: 2}
+status: {observedGeneration: 1, replicas: 2, updatedReplicas: 2, readyReplicas: 2, availableReplicas: 2}
+`, v1alpha1.HealthCurrent),
+		Entry("deployment past its progress deadline", `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: test}
+status: {conditions: [{type: Progressing, status: "False", reason: ProgressDeadlineExceeded}]}
+`, v1alpha1.HealthFailed),
+		Entry("statefulset rolling out", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {name: test}
+spec: {replicas: 1}
+status: {replicas: 1, readyReplicas: 1, availableReplicas: 1, currentRevision: a, updateRevision: b}
+`, v1alpha1.HealthInProgress),
+		Entry("ready statefulset", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {name: test}
+spec: {replicas: 1}
+status: {replicas: 1, readyReplicas: 1, availableReplicas: 1, currentRevision: a, updateRevision: a}
+`, v1alpha1.HealthCurrent),
+		Entry("daemonset with unavailable pods", `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata: {name: test}
+status: {desiredNumberScheduled: 2, currentNumberScheduled: 2, updatedNumberScheduled: 2, numberAvailable: 1, numberReady: 1}
+`, v1alpha1.HealthInProgress),
+		Entry("completed job", `
+apiVersion: batch/v1
+kind: Job
+metadata: {name: test}
+status: {conditions: [{type: Complete, status: "True"}]}
+`, v1alpha1.HealthCurrent),
+		Entry("failed job", `
+apiVersion: batch/v1
+kind: Job
+metadata: {name: test}
+status: {conditions: [{type: Failed, status: "True"}]}
+`, v1alpha1.HealthFailed),
+		Entry("running job", `
+apiVersion: batch/v1
+kind: Job
+metadata: {name: test}
+status: {active: 1}
+`, v1alpha1.HealthInProgress),
+		Entry("pod in CrashLoopBackOff", `
+apiVersion: v1
+kind: Pod
+metadata: {name: test}
+status: {phase: Running, containerStatuses: [{name: app, state: {waiting: {reason: CrashLoopBackOff}}}]}
+`, v1alpha1.HealthFailed),
+		Entry("pending claim", `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata: {name: test}
+status: {phase: Pending}
+`, v1alpha1.HealthInProgress),
+		Entry("load balancer without ingress", `
+apiVersion: v1
+kind: Service
+metadata: {name: test}
+spec: {type: LoadBalancer}
+`, v1alpha1.HealthInProgress),
+		Entry("cluster IP service", `
+apiVersion: v1
+kind: Service
+metadata: {name: test}
+spec: {type: ClusterIP}
+`, v1alpha1.HealthCurrent),
+		Entry("established CRD", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata: {name: widgets.example.com}
+status: {conditions: [{type: Established, status: "True"}]}
+`, v1alpha1.HealthCurrent),
+		Entry("CRD with rejected names", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata: {name: widgets.example.com}
+status: {conditions: [{type: NamesAccepted, status: "False", reason: NameConflict}]}
+`, v1alpha1.HealthFailed),
+		Entry("unavailable APIService", `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata: {name: v1.example.com}
+status: {conditions: [{type: Available, status: "False", reason: MissingEndpoints}]}
+`, v1alpha1.HealthInProgress),
+		Entry("unhealthy pod disruption budget", `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata: {name: test}
+status: {currentHealthy: 1, desiredHealthy: 2}
+`, v1alpha1.HealthInProgress),
+	)
+})
@@ -42,11 +42,8 @@ func (mc *Controller) CreateManifest(ctx context.Context, namespace, name string
 		return err
 	}
 
-	sum, err := mc.getCheckSumUrl(dataBytes)
-	if err != nil {
-		mc.logger.Error(err, "Failed to get checksum for url")
-		return err
-	}
+	sum := Checksum(dataBytes)
+	mc.logger.Info("computed checksum on kustomize build output", "Checksum", sum)
 
 	m := v1alpha1.Manifest{
 		ObjectMeta: metav1.ObjectMeta{
@@ -89,29 +86,14 @@ func (mc *Controller) createOrUpdateManifest(ctx context.Context, m v1alpha1.Man
 		// Use checksum to see if any updates are required.
 		if mc.checkIfManifestNeedsUpdate(m, existing) {
 			mc.logger.Info("manifest crd exists, checksum differs", "Existing", existing.Spec.Checksum, "New", m.Spec.Checksum)
-
 			// This will differ both in case either the url has changed or the contents of the url has changed.
-			// Store the newChecksum to the new computed value and store checksum to the old value.
-			// This value will be reset by manifest controller after the update workflow is completed.
-
-			newManifest := v1alpha1.Manifest{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:            m.Name,
-					Namespace:       m.Namespace,
-					ResourceVersion: existing.ResourceVersion,
-				},
-				Spec: v1alpha1.ManifestSpec{
-					Url:           m.Spec.Url,
-					Checksum:      existing.Spec.Checksum,
-					NewChecksum:   m.Spec.Checksum,
-					Objects:       existing.Spec.Objects,
-					FailurePolicy: m.Spec.FailurePolicy,
-					Timeout:       m.Spec.Timeout,
-					Values:        m.Spec.Values,
-				},
-			}
-			newManifest.SetFinalizers(existing.GetFinalizers())
-			err := mc.client.Update(ctx, &newManifest)
+			// Updating the spec bumps the generation of the manifest, which the manifest controller applies.
+			existing.Spec.Url = m.Spec.Url
+			existing.Spec.Checksum = m.Spec.Checksum
+			existing.Spec.FailurePolicy = m.Spec.FailurePolicy
+			existing.Spec.Timeout = m.Spec.Timeout
+			existing.Spec.Values = m.Spec.Values
+			err := mc.client.Update(ctx, existing)
 			if err != nil {
 				mc.logger.Info("failed to update manifest crd", "Error", err)
 				return err
@@ -119,14 +101,8 @@ func (mc *Controller) createOrUpdateManifest(ctx context.Context, m v1alpha1.Man
 			mc.logger.Info("manifest updated successfully", "ManifestName", m.Name)
 		}
 		return nil
-
 	} else {
 		mc.logger.Info("manifest crd does not exist, creating", "ManifestName", m.Name, "Namespace", m.Namespace)
-
-		// In this case, NewChecksum will be an empty string
-		//m.Spec.NewChecksum = m.Spec.Checksum
-		m.Spec.NewChecksum = ""
-
 		err := mc.client.Create(ctx, &m)
 		if err != nil {
 			mc.logger.Info("failed to create manifest crd", "Error", err)
@@ -164,37 +140,44 @@ func (mc *Controller) getExistingManifest(ctx context.Context, namespace, name s
 	return existing, nil
 }
 
-func (mc *Controller) getCheckSumUrl(kustomizeBytes []byte) (string, error) {
+// Checksum returns the checksum of a rendered manifest
+func Checksum(kustomizeBytes []byte) string {
 	sum := sha256.Sum256(kustomizeBytes)
-	mc.logger.Info("computed checksum on kustomize build output", "Checksum", hex.EncodeToString(sum[:]))
-	return hex.EncodeToString(sum[:]), nil
+	return hex.EncodeToString(sum[:])
 }
 
-func (mc *Controller) DeleteManifest(ctx context.Context, namespace, name, url string) error {
+// DeleteManifest deletes the manifest and returns whether it is gone
+// The manifest is only gone once the manifest controller has deleted its objects and removed its finalizer.
+func (mc *Controller) DeleteManifest(ctx context.Context, namespace, name, url string) (bool, error) {
 
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	existing, err := mc.getExistingManifest(ctx, namespace, name)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if existing == nil {
 		mc.logger.Info("manifest object does not exist", "Name", name)
-		return nil
+		return true, nil
 
 	}
 
+	if !existing.DeletionTimestamp.IsZero() {
+		mc.logger.Info("waiting for the objects of the manifest to be deleted", "ManifestName", name)
+		return false, nil
+	}
+
 	mc.logger.Info("deleting the manifest crd", "ManifestName", name, "Namespace", namespace)
 
 	err = mc.client.Delete(ctx, existing)
 	if err != nil {
 		mc.logger.Info("failed to delete manifest crd", "Error", err)
-		return err
+		return false, err
 	}
-	mc.logger.Info("manifest deleted successfully", "ManifestName", name)
+	mc.logger.Info("manifest is being deleted", "ManifestName", name)
 
-	return nil
+	return false, nil
 
 }
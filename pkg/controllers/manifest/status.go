@@ -3,10 +3,12 @@ package manifest
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
-	appsv1 "k8s.io/api/apps/v1"
-	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
@@ -16,159 +18,77 @@ type Status struct {
 	StatusType v1alpha1.StatusType
 	Reason     string
 	Message    string
+	Health     []v1alpha1.ManifestObjectHealth
 }
 
-// CheckManifestStatus checks the status of any deployments and daemonsets associated with the namespacedName manifest
-// Check the status of the deployment and daemonset and set the manifest to an error state if any errors are found
-// If no errors are found, we check if any deployments/daemonsets are still progressing and set the manifest status to Progressing
-// Otherwise set the manifest status to Available
-// This is not comprehensive and may need to be updated as we support more complex manifests
+// CheckManifestStatus checks the health of every object of the manifest
+// The health of each object is computed with ObjectHealth and returned along with the aggregated status.
+// If any object has failed, the manifest is Unhealthy. If any object is still in progress, terminating or missing,
+// the manifest is Progressing. Otherwise, the manifest is Available.
 func (mc *Controller) CheckManifestStatus(ctx context.Context, logger logr.Logger, objects []v1alpha1.ManifestObject) (Status, error) {
 
-	if objects == nil || len(objects) == 0 {
+	if len(objects) == 0 {
 		logger.Info("No manifest objects for manifest")
-		return Status{v1alpha1.TypeComponentUnhealthy, "No objects detected for manifest", ""}, nil
+		return Status{StatusType: v1alpha1.TypeComponentUnhealthy, Reason: "No objects detected for manifest"}, nil
 	}
 
-	// split objects into deployments and daemonsets, which are the two resources we are looking at for status currently since
-	// they have reliable status fields
-	var deployments, daemonsets []v1alpha1.ManifestObject
+	health := make([]v1alpha1.ManifestObjectHealth, 0, len(objects))
+	var failed, progressing []string
 	for _, obj := range objects {
-		if obj.Kind == "Deployment" {
-			deployments = append(deployments, obj)
-		} else if obj.Kind == "DaemonSet" {
-			daemonsets = append(daemonsets, obj)
+		h, err := mc.checkObjectHealth(ctx, obj)
+		if err != nil {
+			return Status{StatusType: v1alpha1.TypeComponentUnhealthy, Reason: "Unable to get manifest object", Message: err.Error()}, err
+		}
+		health = append(health, h)
+
+		switch h.Health {
+		case v1alpha1.HealthCurrent:
+		case v1alpha1.HealthFailed:
+			failed = append(failed, describeObjectHealth(h))
+		default:
+			progressing = append(progressing, describeObjectHealth(h))
 		}
 	}
 
-	deploymentStatus, err := mc.checkManifestDeployments(ctx, logger, deployments)
-	if err != nil {
-		return deploymentStatus, err
-	}
-
-	daemonsetStatus, err := mc.checkManifestDaemonsets(ctx, logger, daemonsets)
-	if err != nil {
-		return daemonsetStatus, err
-	}
-
-	if deploymentStatus.StatusType == v1alpha1.TypeComponentUnhealthy {
-		return deploymentStatus, nil
-	}
-
-	if daemonsetStatus.StatusType == v1alpha1.TypeComponentUnhealthy {
-		return daemonsetStatus, nil
+	if len(failed) > 0 {
+		return Status{v1alpha1.TypeComponentUnhealthy, "1 or more manifest objects failed", strings.Join(failed, "; "), health}, nil
 	}
 
-	if deploymentStatus.StatusType == v1alpha1.TypeComponentProgressing && daemonsetStatus.StatusType == v1alpha1.TypeComponentProgressing {
-		return Status{v1alpha1.TypeComponentProgressing, "Manifest Components Still Progressing", fmt.Sprintf("Deployments : %s, Daemonsets : %s", deploymentStatus.Reason, daemonsetStatus.Reason)}, nil
-	} else if deploymentStatus.StatusType == v1alpha1.TypeComponentProgressing {
-		return deploymentStatus, nil
-	} else if daemonsetStatus.StatusType == v1alpha1.TypeComponentProgressing {
-		return daemonsetStatus, nil
+	if len(progressing) > 0 {
+		return Status{v1alpha1.TypeComponentProgressing, "1 or more manifest objects are still progressing", strings.Join(progressing, "; "), health}, nil
 	}
 
-	// if we got here then both deployments and daemonsets are Available
-
-	return Status{v1alpha1.TypeComponentAvailable, "Manifest Components Available", fmt.Sprintf("Deployments : %s, Daemonsets : %s", deploymentStatus.Reason, daemonsetStatus.Reason)}, nil
+	return Status{v1alpha1.TypeComponentAvailable, "Manifest Components Available", fmt.Sprintf("%d objects are current", len(health)), health}, nil
 }
 
-func (mc *Controller) checkManifestDeployments(ctx context.Context, logger logr.Logger, deployments []v1alpha1.ManifestObject) (Status, error) {
+// checkObjectHealth gets the manifest object from the cluster and computes its health
+func (mc *Controller) checkObjectHealth(ctx context.Context, obj v1alpha1.ManifestObject) (v1alpha1.ManifestObjectHealth, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: obj.Group, Version: obj.Version, Kind: obj.Kind})
 
-	if len(deployments) == 0 {
-		return Status{}, nil
+	err := mc.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, u)
+	if apierrors.IsNotFound(err) {
+		return v1alpha1.ManifestObjectHealth{ManifestObject: obj, Health: v1alpha1.HealthNotFound, Message: "object not found"}, nil
 	}
-
-	progressCount := 0
-
-	for _, obj := range deployments {
-
-		deployment := &appsv1.Deployment{}
-		err := mc.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, deployment)
-		if err != nil {
-			return Status{v1alpha1.TypeComponentUnhealthy, "Unable to get deployment from manifest", ""}, err
-		}
-		if deployment.Status.AvailableReplicas == deployment.Status.Replicas && (deployment.Status.Conditions == nil || len(deployment.Status.Conditions) == 0) {
-			// this deployment is ready
-			continue
-		}
-
-		progressCondition, err := getConditionOfType(appsv1.DeploymentProgressing, deployment.Status.Conditions)
-		if err != nil {
-			return Status{v1alpha1.TypeComponentUnhealthy, "Unable to get deployment conditions from manifest", ""}, err
-		}
-
-		availableCondition, err := getConditionOfType(appsv1.DeploymentAvailable, deployment.Status.Conditions)
-		if err != nil {
-			return Status{v1alpha1.TypeComponentUnhealthy, "Unable to get deployment conditions from manifest", ""}, err
-		}
-
-		if deployment.Status.AvailableReplicas == deployment.Status.Replicas && availableCondition.Status == v1.ConditionTrue {
-			// this deployment is ready
-			continue
-		}
-
-		// if progress condition is not true, then progress deadline has not yet expired for the deployment
-		if progressCondition.Status == v1.ConditionTrue {
-			progressCount++
-		} else {
-			// progress deadline has expired for deployment, so we can return error status for deployments
-			return Status{v1alpha1.TypeComponentUnhealthy, progressCondition.Reason, progressCondition.Message}, nil
-
-		}
-	}
-
-	if progressCount > 0 {
-		// if even 1 deployment is still progressing we should return progressing status
-		return Status{v1alpha1.TypeComponentProgressing, "1 or more manifest deployments are still progressing", ""}, nil
+	if err != nil {
+		return v1alpha1.ManifestObjectHealth{}, fmt.Errorf("failed to get %s %s/%s: %w", obj.Kind, obj.Namespace, obj.Name, err)
 	}
 
-	return Status{v1alpha1.TypeComponentAvailable, "Manifest Deployments Available", ""}, nil
-}
-
-func getConditionOfType(desiredType appsv1.DeploymentConditionType, conditions []appsv1.DeploymentCondition) (appsv1.DeploymentCondition, error) {
-	for _, condition := range conditions {
-		if condition.Type == desiredType {
-			return condition, nil
-		}
+	status, message, err := ObjectHealth(u)
+	if err != nil {
+		return v1alpha1.ManifestObjectHealth{}, err
 	}
 
-	return appsv1.DeploymentCondition{}, fmt.Errorf("condition type unavailable")
+	return v1alpha1.ManifestObjectHealth{ManifestObject: obj, Health: status, Message: message}, nil
 }
 
-func (mc *Controller) checkManifestDaemonsets(ctx context.Context, logger logr.Logger, daemonsets []v1alpha1.ManifestObject) (Status, error) {
-
-	if len(daemonsets) == 0 {
-		return Status{}, nil
-	}
-
-	progressCount := 0
-
-	for _, obj := range daemonsets {
-
-		daemonset := &appsv1.DaemonSet{}
-		err := mc.client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, daemonset)
-		if err != nil {
-			return Status{v1alpha1.TypeComponentUnhealthy, "Unable to get daemonset from manifest", ""}, err
-		}
-
-		if daemonset.Status.DesiredNumberScheduled == daemonset.Status.NumberReady && daemonset.Status.DesiredNumberScheduled == daemonset.Status.NumberAvailable {
-			//daemonset is ready
-			continue
-		}
-
-		if daemonset.Status.NumberMisscheduled > 0 || daemonset.Status.NumberUnavailable > 0 {
-			if err != nil {
-				return Status{v1alpha1.TypeComponentUnhealthy, fmt.Sprintf("Daemonset %s failed to schedule pods", daemonset.Name), ""}, err
-			}
-		}
-
-		progressCount++
+func describeObjectHealth(h v1alpha1.ManifestObjectHealth) string {
+	name := h.Name
+	if h.Namespace != "" {
+		name = h.Namespace + "/" + h.Name
 	}
-
-	if progressCount > 0 {
-		// if even 1 daemonset is still progressing we should return progressing status
-		return Status{v1alpha1.TypeComponentProgressing, "1 or more manifest daemonsets are still progressing", ""}, nil
+	if h.Message == "" {
+		return fmt.Sprintf("%s %s is %s", h.Kind, name, h.Health)
 	}
-
-	return Status{v1alpha1.TypeComponentAvailable, "Manifest Daemonsets Available", ""}, nil
+	return fmt.Sprintf("%s %s is %s: %s", h.Kind, name, h.Health, h.Message)
 }
@@ -7,237 +7,193 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
-	"github.com/mirantiscontainers/blueprint-operator/test/mocks"
 )
 
 var _ = Describe("Status", func() {
 	var (
-		m      *mocks.MockClient
 		mc     *Controller
 		logger logr.Logger
+
+		deploymentObject = v1alpha1.ManifestObject{Group: "apps", Version: "v1", Kind: "Deployment", Name: "TestDeployment", Namespace: "TestNamespace"}
+		daemonsetObject  = v1alpha1.ManifestObject{Group: "apps", Version: "v1", Kind: "DaemonSet", Name: "TestDaemonset", Namespace: "TestNamespace"}
+		configMapObject  = v1alpha1.ManifestObject{Version: "v1", Kind: "ConfigMap", Name: "TestConfigMap", Namespace: "TestNamespace"}
 	)
 
+	newDeployment := func(ready bool) *appsv1.Deployment {
+		replicas := int32(1)
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "TestDeployment", Namespace: "TestNamespace", Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1},
+		}
+		if ready {
+			d.Status.ReadyReplicas = 1
+			d.Status.AvailableReplicas = 1
+		}
+		return d
+	}
+
+	newDaemonset := func(ready bool) *appsv1.DaemonSet {
+		d := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "TestDaemonset", Namespace: "TestNamespace", Generation: 1},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 1,
+				CurrentNumberScheduled: 1,
+				UpdatedNumberScheduled: 1,
+			},
+		}
+		if ready {
+			d.Status.NumberReady = 1
+			d.Status.NumberAvailable = 1
+		}
+		return d
+	}
+
+	newController := func(objs ...client.Object) {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+		mc = NewManifestController(c, logger)
+	}
+
 	BeforeEach(func() {
-		m = mocks.NewMockClient()
 		logger = log.FromContext(context.TODO())
-		mc = NewManifestController(m, logger)
 	})
 
 	Context("ErrorTest", func() {
 		Context("No manifest objects", func() {
 			It("Should return unhealthy status", func() {
+				newController()
 				stat, err := mc.CheckManifestStatus(context.TODO(), logger, nil)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(stat).Should(Equal(Status{v1alpha1.TypeComponentUnhealthy, "No objects detected for manifest", ""}))
+				Expect(stat).Should(Equal(Status{StatusType: v1alpha1.TypeComponentUnhealthy, Reason: "No objects detected for manifest"}))
 			})
 		})
 
 		Context("Error when retrieving deployment belonging to manifest", func() {
 			It("Should return unhealthy status", func() {
-				manifestObjects := []v1alpha1.ManifestObject{
-					{
-						Kind:      "Deployment",
-						Name:      "TestDeployment",
-						Namespace: "TestNamespace",
+				c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, _ client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						return fmt.Errorf("error")
 					},
-				}
+				}).Build()
+				mc = NewManifestController(c, logger)
 
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDeployment"},
-					&appsv1.Deployment{},
-					mock.Anything,
-				).Return(fmt.Errorf("error"))
-
-				stat, err := mc.CheckManifestStatus(context.TODO(), logger, manifestObjects)
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject})
 				Expect(err).To(HaveOccurred())
-				Expect(stat).Should(Equal(Status{v1alpha1.TypeComponentUnhealthy, "Unable to get deployment from manifest", ""}))
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentUnhealthy))
+				Expect(stat.Reason).Should(Equal("Unable to get manifest object"))
 			})
+		})
 
+		Context("Deployment exceeded its progress deadline", func() {
+			It("Should return unhealthy status", func() {
+				d := newDeployment(false)
+				d.Status.Conditions = []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+				}
+				newController(d)
+
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentUnhealthy))
+				Expect(stat.Reason).Should(Equal("1 or more manifest objects failed"))
+				Expect(stat.Health).Should(HaveLen(1))
+				Expect(stat.Health[0].Health).Should(Equal(v1alpha1.HealthFailed))
+			})
 		})
 	})
 
 	Context("Manifest still Progressing", func() {
 		Context("Single deployment manifest still progressing", func() {
 			It("Should return manifest status as still progressing", func() {
-				manifestObjects := []v1alpha1.ManifestObject{
-					{
-						Kind:      "Deployment",
-						Name:      "TestDeployment",
-						Namespace: "TestNamespace",
-					},
-				}
+				newController(newDeployment(false))
 
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDeployment"},
-					&appsv1.Deployment{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					deployment := args.Get(2).(*appsv1.Deployment)
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue})
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse})
-				})
-
-				stat, err := mc.CheckManifestStatus(context.TODO(), logger, manifestObjects)
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(stat).Should(Equal(Status{v1alpha1.TypeComponentProgressing, "1 or more manifest deployments are still progressing", ""}))
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentProgressing))
+				Expect(stat.Reason).Should(Equal("1 or more manifest objects are still progressing"))
+				Expect(stat.Message).Should(ContainSubstring("Deployment TestNamespace/TestDeployment is InProgress"))
 			})
 		})
 
 		Context("Deployment is available but Daemonset still progressing", func() {
 			It("Should return manifest status as still progressing", func() {
-				manifestObjects := []v1alpha1.ManifestObject{
-					{
-						Kind:      "Deployment",
-						Name:      "TestDeployment",
-						Namespace: "TestNamespace",
-					},
-					{
-						Kind:      "DaemonSet",
-						Name:      "TestDaemonset",
-						Namespace: "TestNamespace",
-					},
-				}
+				newController(newDeployment(true), newDaemonset(false))
 
-				// Mock out that Deployment is Done
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDeployment"},
-					&appsv1.Deployment{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					deployment := args.Get(2).(*appsv1.Deployment)
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue})
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse})
-
-				})
-
-				// Mock out Daemonset still Progressing
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDaemonset"},
-					&appsv1.DaemonSet{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					daemonset := args.Get(2).(*appsv1.DaemonSet)
-					daemonset.Status.NumberAvailable = 0
-					daemonset.Status.NumberReady = 0
-					daemonset.Status.DesiredNumberScheduled = 1
-				})
-
-				stat, err := mc.CheckManifestStatus(context.TODO(), logger, manifestObjects)
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject, daemonsetObject})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(stat).Should(Equal(Status{v1alpha1.TypeComponentProgressing, "1 or more manifest daemonsets are still progressing", ""}))
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentProgressing))
+				Expect(stat.Message).Should(ContainSubstring("DaemonSet TestNamespace/TestDaemonset is InProgress"))
+				Expect(stat.Message).ShouldNot(ContainSubstring("Deployment"))
 			})
 		})
 
 		Context("Daemonset is available but Deployment is still progressing", func() {
 			It("Should return manifest status as still progressing", func() {
-				manifestObjects := []v1alpha1.ManifestObject{
-					{
-						Kind:      "Deployment",
-						Name:      "TestDeployment",
-						Namespace: "TestNamespace",
-					},
-					{
-						Kind:      "DaemonSet",
-						Name:      "TestDaemonset",
-						Namespace: "TestNamespace",
-					},
-				}
+				newController(newDeployment(false), newDaemonset(true))
 
-				// Mock out that Deployment is Done
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDeployment"},
-					&appsv1.Deployment{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					deployment := args.Get(2).(*appsv1.Deployment)
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue})
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse})
-
-				})
-
-				// Mock out Daemonset still Available
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDaemonset"},
-					&appsv1.DaemonSet{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					daemonset := args.Get(2).(*appsv1.DaemonSet)
-					daemonset.Status.NumberAvailable = 1
-					daemonset.Status.NumberReady = 1
-					daemonset.Status.DesiredNumberScheduled = 1
-				})
-
-				stat, err := mc.CheckManifestStatus(context.TODO(), logger, manifestObjects)
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject, daemonsetObject})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(stat).Should(Equal(Status{v1alpha1.TypeComponentProgressing, "1 or more manifest deployments are still progressing", ""}))
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentProgressing))
+				Expect(stat.Message).Should(ContainSubstring("Deployment TestNamespace/TestDeployment is InProgress"))
+				Expect(stat.Message).ShouldNot(ContainSubstring("DaemonSet"))
+			})
+		})
+
+		Context("Deployment status was not observed for the latest generation", func() {
+			It("Should return manifest status as still progressing", func() {
+				d := newDeployment(true)
+				d.Generation = 2
+				newController(d)
+
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentProgressing))
+			})
+		})
+
+		Context("Manifest object does not exist", func() {
+			It("Should return manifest status as still progressing", func() {
+				newController(newDeployment(true))
+
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, []v1alpha1.ManifestObject{deploymentObject, configMapObject})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stat.StatusType).Should(Equal(v1alpha1.TypeComponentProgressing))
+				Expect(stat.Health[1].Health).Should(Equal(v1alpha1.HealthNotFound))
 			})
 		})
 	})
 
 	Context("Manifest is Available", func() {
-		Context("Deployment & Daemonset are both available", func() {
+		Context("Deployment, Daemonset and ConfigMap are all current", func() {
 			It("Should return manifest status as available", func() {
-				manifestObjects := []v1alpha1.ManifestObject{
-					{
-						Kind:      "Deployment",
-						Name:      "TestDeployment",
-						Namespace: "TestNamespace",
-					},
-					{
-						Kind:      "DaemonSet",
-						Name:      "TestDaemonset",
-						Namespace: "TestNamespace",
-					},
-				}
+				configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "TestConfigMap", Namespace: "TestNamespace"}}
+				newController(newDeployment(true), newDaemonset(true), configMap)
 
-				// Mock out that Deployment is Done
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDeployment"},
-					&appsv1.Deployment{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					deployment := args.Get(2).(*appsv1.Deployment)
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue})
-					deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse})
-				})
-
-				// Mock out Daemonset is Done
-				m.On("Get",
-					context.TODO(),
-					types.NamespacedName{Namespace: "TestNamespace", Name: "TestDaemonset"},
-					&appsv1.DaemonSet{},
-					mock.Anything,
-				).Return(nil).Run(func(args mock.Arguments) {
-					daemonset := args.Get(2).(*appsv1.DaemonSet)
-					daemonset.Status.NumberAvailable = 1
-					daemonset.Status.NumberReady = 1
-					daemonset.Status.DesiredNumberScheduled = 1
-				})
-
-				stat, err := mc.CheckManifestStatus(context.TODO(), logger, manifestObjects)
+				objects := []v1alpha1.ManifestObject{deploymentObject, daemonsetObject, configMapObject}
+				stat, err := mc.CheckManifestStatus(context.TODO(), logger, objects)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(stat).Should(Equal(Status{
-					v1alpha1.TypeComponentAvailable,
-					"Manifest Components Available",
-					"Deployments : Manifest Deployments Available, Daemonsets : Manifest Daemonsets Available",
+					StatusType: v1alpha1.TypeComponentAvailable,
+					Reason:     "Manifest Components Available",
+					Message:    "3 objects are current",
+					Health: []v1alpha1.ManifestObjectHealth{
+						{ManifestObject: deploymentObject, Health: v1alpha1.HealthCurrent},
+						{ManifestObject: daemonsetObject, Health: v1alpha1.HealthCurrent},
+						{ManifestObject: configMapObject, Health: v1alpha1.HealthCurrent},
+					},
 				}))
-
 			})
 		})
 	})
-
 })
@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+)
+
+var _ = Describe("Timeout", func() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	Context("NewOperation", func() {
+		It("Should set the deadline from the timeout", func() {
+			op, err := NewOperation(v1alpha1.ManifestOperationInstall, "5m", 2, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op.Type).To(Equal(v1alpha1.ManifestOperationInstall))
+			Expect(op.Attempts).To(Equal(int32(2)))
+			Expect(op.StartTime.Time).To(Equal(now))
+			Expect(op.Deadline).NotTo(BeNil())
+			Expect(op.Deadline.Time).To(Equal(now.Add(5 * time.Minute)))
+		})
+
+		It("Should not set a deadline without a timeout", func() {
+			op, err := NewOperation(v1alpha1.ManifestOperationUpgrade, "", 1, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op.Deadline).To(BeNil())
+		})
+
+		It("Should return an error for an invalid timeout", func() {
+			_, err := NewOperation(v1alpha1.ManifestOperationUpgrade, "5 minutes", 1, now)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("CheckOperation", func() {
+		var manifest *v1alpha1.Manifest
+
+		BeforeEach(func() {
+			op, err := NewOperation(v1alpha1.ManifestOperationInstall, "5m", 1, now)
+			Expect(err).NotTo(HaveOccurred())
+			manifest = &v1alpha1.Manifest{
+				Spec: v1alpha1.ManifestSpec{FailurePolicy: FailurePolicyRetry, Timeout: "5m"},
+				Status: v1alpha1.ManifestStatus{
+					Status:    v1alpha1.Status{Type: v1alpha1.TypeComponentProgressing},
+					Operation: op,
+				},
+			}
+		})
+
+		It("Should do nothing without an operation", func() {
+			manifest.Status.Operation = nil
+			action, _ := CheckOperation(manifest, now)
+			Expect(action).To(Equal(OperationActionNone))
+		})
+
+		It("Should do nothing for a completed operation", func() {
+			completionTime := metav1.NewTime(now)
+			manifest.Status.Operation.CompletionTime = &completionTime
+			action, _ := CheckOperation(manifest.DeepCopy(), now.Add(time.Hour))
+			Expect(action).To(Equal(OperationActionNone))
+		})
+
+		It("Should complete the operation once the manifest is available", func() {
+			manifest.Status.Type = v1alpha1.TypeComponentAvailable
+			action, _ := CheckOperation(manifest, now.Add(time.Minute))
+			Expect(action).To(Equal(OperationActionComplete))
+		})
+
+		It("Should wait until the deadline", func() {
+			action, remaining := CheckOperation(manifest, now.Add(time.Minute))
+			Expect(action).To(Equal(OperationActionWait))
+			Expect(remaining).To(Equal(4 * time.Minute))
+		})
+
+		It("Should not time out an operation without a deadline", func() {
+			manifest.Status.Operation.Deadline = nil
+			action, _ := CheckOperation(manifest, now.Add(time.Hour))
+			Expect(action).To(Equal(OperationActionNone))
+		})
+
+		It("Should retry after the deadline with the Retry policy", func() {
+			action, _ := CheckOperation(manifest, now.Add(5*time.Minute))
+			Expect(action).To(Equal(OperationActionRetry))
+		})
+
+		It("Should fail after the deadline with the None policy", func() {
+			manifest.Spec.FailurePolicy = FailurePolicyNone
+			action, _ := CheckOperation(manifest, now.Add(10*time.Minute))
+			Expect(action).To(Equal(OperationActionFail))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package dag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Graph is a directed acyclic graph of named nodes, where each node points to the nodes it depends on
+type Graph struct {
+	nodes []string
+	edges map[string][]string
+}
+
+// CycleError is returned when the dependencies of the graph form a cycle
+type CycleError struct {
+	// Cycle is the path of the cycle, the first and last node are the same
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// New returns an empty graph
+func New() *Graph {
+	return &Graph{edges: map[string][]string{}}
+}
+
+// AddNode adds a node along with the nodes it depends on
+// Adding an existing node appends to its dependencies.
+func (g *Graph) AddNode(name string, dependsOn ...string) {
+	if _, ok := g.edges[name]; !ok {
+		g.nodes = append(g.nodes, name)
+		g.edges[name] = nil
+	}
+	g.edges[name] = append(g.edges[name], dependsOn...)
+}
+
+// TopologicalSort returns the nodes ordered so that every node comes after its dependencies
+// Nodes without a relative order keep the order in which they were added. A CycleError is returned if
+// the graph has a cycle, and an error if a node depends on a node that is not part of the graph.
+func (g *Graph) TopologicalSort() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(g.nodes))
+	sorted := make([]string, 0, len(g.nodes))
+	var stack []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range stack {
+				if n == name {
+					cycle := append([]string{}, stack[i:]...)
+					return &CycleError{Cycle: append(cycle, name)}
+				}
+			}
+		}
+
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range g.edges[name] {
+			if _, ok := g.edges[dep]; !ok {
+				return fmt.Errorf("%s depends on %s which does not exist", name, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		sorted = append(sorted, name)
+		return nil
+	}
+
+	for _, name := range g.nodes {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
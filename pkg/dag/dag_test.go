@@ -0,0 +1,66 @@
+package dag
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodes         [][]string
+		expected      []string
+		expectedCycle []string
+		expectError   bool
+	}{
+		{
+			name:     "no dependencies keeps insertion order",
+			nodes:    [][]string{{"a"}, {"b"}, {"c"}},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "dependencies come first",
+			nodes:    [][]string{{"app", "cni", "csi"}, {"csi", "cni"}, {"cni"}},
+			expected: []string{"cni", "csi", "app"},
+		},
+		{
+			name:          "self dependency",
+			nodes:         [][]string{{"a", "a"}},
+			expectedCycle: []string{"a", "a"},
+		},
+		{
+			name:          "cycle",
+			nodes:         [][]string{{"a", "b"}, {"b", "c"}, {"c", "a"}},
+			expectedCycle: []string{"a", "b", "c", "a"},
+		},
+		{
+			name:        "missing dependency",
+			nodes:       [][]string{{"a", "b"}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := New()
+			for _, n := range tc.nodes {
+				g.AddNode(n[0], n[1:]...)
+			}
+
+			sorted, err := g.TopologicalSort()
+			switch {
+			case tc.expectedCycle != nil:
+				var cycleErr *CycleError
+				assert.True(t, errors.As(err, &cycleErr))
+				assert.Equal(t, tc.expectedCycle, cycleErr.Cycle)
+			case tc.expectError:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, sorted)
+			}
+		})
+	}
+}
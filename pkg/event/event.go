@@ -5,6 +5,11 @@ const AddonAnnotationKey = "Addon"
 const ReasonSuccessfulCreate = "SuccessfulCreate"
 const ReasonFailedCreate = "FailedCreate"
 const ReasonFailedDelete = "FailedDelete"
+const ReasonSuccessfulDryRun = "SuccessfulDryRun"
+const ReasonFailedDryRun = "FailedDryRun"
+const ReasonFieldConflict = "FieldConflict"
+const ReasonSuccessfulRepair = "SuccessfulRepair"
+const ReasonFailedRepair = "FailedRepair"
 
 const TypeWarning = "Warning"
 const TypeNormal = "Normal"
@@ -2,32 +2,119 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// defaultCRDEstablishTimeout is the time to wait for applied CRDs to be established
+	defaultCRDEstablishTimeout = 30 * time.Second
+	crdEstablishPollInterval   = 1 * time.Second
+)
+
+var (
+	// legacyFieldManagers are the field managers of earlier versions of the operator, which created and updated
+	// the objects instead of applying them. The manager name is derived from the name of the operator binary.
+	legacyFieldManagers = []string{"manager"}
+
+	// conflictManagerRegexp extracts the field manager from the message of a field conflict,
+	// e.g. conflict with "kubectl-client-side-apply" using apps/v1
+	conflictManagerRegexp = regexp.MustCompile(`^conflict with "([^"]*)"`)
+)
+
+// ObjectError is the error of an object that failed to be applied
+type ObjectError struct {
+	Object *unstructured.Unstructured
+	Err    error
+}
+
+func (e ObjectError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Object.GetKind(), client.ObjectKeyFromObject(e.Object), e.Err)
+}
+
+func (e ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyErrors aggregates the errors of the objects that failed to be applied
+type ApplyErrors []ObjectError
+
+func (e ApplyErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("failed to apply %d objects: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Conflict describes the fields of an object that are owned by other field managers,
+// preventing the object from being applied with server-side apply
+type Conflict struct {
+	Object *unstructured.Unstructured
+	// Fields lists the conflicting fields along with the managers that owned them
+	Fields []string
+}
+
 // Applier is used to create/update/delete one or more objects from a YAML manifest file to the cluster
-// @TODO: Remove this in favor of Client in client.go which provides server side apply
+// Use ServerSideApply to apply objects with server side apply.
 type Applier struct {
 	log    logr.Logger
 	client client.Client
+
+	// crdEstablishTimeout is the time to wait for applied CRDs to be established
+	crdEstablishTimeout time.Duration
+	// continueOnError makes the applier apply all objects even if some of them fail
+	continueOnError bool
+	// labels are set on all the applied objects
+	labels map[string]string
+}
+
+// ApplierOption configures an Applier
+type ApplierOption func(*Applier)
+
+// WithContinueOnError makes the Applier apply all objects even if some of them fail to be applied
+// The failures are then returned as ApplyErrors.
+func WithContinueOnError() ApplierOption {
+	return func(a *Applier) {
+		a.continueOnError = true
+	}
+}
+
+// WithLabels makes the Applier set the labels on all the objects it applies
+func WithLabels(labels map[string]string) ApplierOption {
+	return func(a *Applier) {
+		a.labels = labels
+	}
 }
 
 // NewApplier creates an Applier instance
-func NewApplier(logger logr.Logger, client client.Client) *Applier {
-	return &Applier{
-		log:    logger,
-		client: client,
+func NewApplier(logger logr.Logger, client client.Client, opts ...ApplierOption) *Applier {
+	a := &Applier{
+		log:                 logger,
+		client:              client,
+		crdEstablishTimeout: defaultCRDEstablishTimeout,
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	return a
 }
 
 // Apply reads the manifest objects from the reader, and then either create or update
 // the objects in the cluster.
-// @TODO: Continue on failure to create/update object and return failed objects
 func (a *Applier) Apply(ctx context.Context, reader UnstructuredReader) error {
 	var err error
 
@@ -36,35 +123,210 @@ func (a *Applier) Apply(ctx context.Context, reader UnstructuredReader) error {
 		return fmt.Errorf("failed to decode objects: %w", err)
 	}
 
+	return a.applyObjects(ctx, objs, a.createOrUpdateObject)
+}
+
+// ApplyObjects create or update the provided objects in the cluster.
+func (a *Applier) ApplyObjects(ctx context.Context, objs []*unstructured.Unstructured) error {
+	for _, o := range objs {
+		if err := a.createOrUpdateObject(ctx, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServerSideApply applies the provided objects in the cluster using server-side apply with the
+// blueprint-operator field owner. CRDs are applied before the other objects.
+// Fields that are not part of the objects are left to their owners. If fields of an object are owned by
+// other field managers, the object is not applied: its conflicts are returned along with an error for the object.
+// Only fields owned by the legacy field managers of the operator are taken over.
+func (a *Applier) ServerSideApply(ctx context.Context, objs []*unstructured.Unstructured) ([]Conflict, error) {
+	var conflicts []Conflict
+
+	err := a.applyObjects(ctx, objs, func(ctx context.Context, o *unstructured.Unstructured) error {
+		conflict, err := a.serverSideApplyObject(ctx, o)
+		if conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+		return err
+	})
+
+	return conflicts, err
+}
+
+// applyObjects applies the objects with the provided function. CRDs are applied first, and the other objects
+// are applied once the CRDs are established.
+// Unless the applier continues on error, it stops at the first object that fails to be applied. Otherwise,
+// all objects are applied and the failures are returned as ApplyErrors.
+func (a *Applier) applyObjects(ctx context.Context, objs []*unstructured.Unstructured, apply func(context.Context, *unstructured.Unstructured) error) error {
+	var errs ApplyErrors
+
 	// separate out the CRDs and other objects
 	// CRDs need to be created first
 	crds, others := a.splitCrdAndOthers(objs)
 	a.log.Info("Found objects", "CRD Objects", len(crds), "Other Objects", len(others))
+
+	var appliedCRDs []*unstructured.Unstructured
 	for _, o := range crds {
-		if err = a.createOrUpdateObject(ctx, o); err != nil {
-			return fmt.Errorf("failed to apply %s crds resources from manifest: %w", o.GetName(), err)
+		if err := apply(ctx, o); err != nil {
+			if !a.continueOnError {
+				return fmt.Errorf("failed to apply %s crds resources from manifest: %w", o.GetName(), err)
+			}
+			errs = append(errs, ObjectError{Object: o, Err: err})
+			continue
 		}
+		appliedCRDs = append(appliedCRDs, o)
 	}
 
-	// @todo wait for crds to be available before creating other objects
+	// wait for crds to be available before creating other objects
+	for _, o := range a.waitForCRDs(ctx, appliedCRDs) {
+		if !a.continueOnError {
+			return o.Err
+		}
+		errs = append(errs, o)
+	}
 
 	// create other objects
 	for _, o := range others {
-		if err = a.createOrUpdateObject(ctx, o); err != nil {
-			return fmt.Errorf("failed to apply '%s/%s' resources in namespace '%s' from manifest at: %w", o.GetKind(), o.GetName(), o.GetNamespace(), err)
+		if err := apply(ctx, o); err != nil {
+			if !a.continueOnError {
+				return fmt.Errorf("failed to apply '%s/%s' resources in namespace '%s' from manifest at: %w", o.GetKind(), o.GetName(), o.GetNamespace(), err)
+			}
+			errs = append(errs, ObjectError{Object: o, Err: err})
 		}
 	}
+
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-// ApplyObjects create or update the provided objects in the cluster.
-func (a *Applier) ApplyObjects(ctx context.Context, objs []*unstructured.Unstructured) error {
-	for _, o := range objs {
-		if err := a.createOrUpdateObject(ctx, o); err != nil {
-			return err
+// waitForCRDs waits for the provided CRDs to be established and for their kinds to be known to the REST mapper
+// of the client, so that custom resources of these kinds can be applied right after.
+// It returns an error for each CRD that was not established in time.
+func (a *Applier) waitForCRDs(ctx context.Context, crds []*unstructured.Unstructured) []ObjectError {
+	if len(crds) == 0 {
+		return nil
+	}
+
+	// forget the cached mappings, so that the kinds of the new CRDs are discovered
+	if mapper, ok := a.client.RESTMapper().(meta.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+
+	var errs []ObjectError
+	for _, crd := range crds {
+		a.log.V(1).Info("Waiting for CRD to be established", "Name", crd.GetName())
+		err := wait.PollUntilContextTimeout(ctx, crdEstablishPollInterval, a.crdEstablishTimeout, true, a.crdEstablishedFunc(crd.GetName()))
+		if err != nil {
+			errs = append(errs, ObjectError{Object: crd, Err: fmt.Errorf("CRD %s was not established within %s: %w", crd.GetName(), a.crdEstablishTimeout, err)})
 		}
 	}
-	return nil
+
+	return errs
+}
+
+func (a *Applier) crdEstablishedFunc(name string) wait.ConditionWithContextFunc {
+	return func(ctx context.Context) (bool, error) {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+		if err := a.client.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+		established := false
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == "Established" && condition["status"] == "True" {
+				established = true
+			}
+		}
+		if !established {
+			return false, nil
+		}
+
+		// the REST mapper discovers the kind of the CRD lazily, which may lag behind the condition
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		var versions []string
+		crdVersions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, v := range crdVersions {
+			if version, ok := v.(map[string]interface{}); ok && version["served"] == true {
+				versions = append(versions, fmt.Sprint(version["name"]))
+			}
+		}
+		if _, err := a.client.RESTMapper().RESTMapping(schema.GroupKind{Group: group, Kind: kind}, versions...); err != nil {
+			if meta.IsNoMatchError(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+func (a *Applier) serverSideApplyObject(ctx context.Context, obj *unstructured.Unstructured) (*Conflict, error) {
+	a.setLabels(obj)
+	a.log.V(1).Info("Applying object", "GroupVersionKind", obj.GroupVersionKind(), "Name", obj.GetName())
+	err := a.client.Patch(ctx, obj, client.Apply, defaultFieldOwner)
+	if err == nil || !apierrors.IsConflict(err) {
+		return nil, err
+	}
+
+	fields, managers := conflictingFields(err)
+	if !onlyLegacyFieldManagers(managers) {
+		a.log.Info("Field conflicts while applying object", "GroupVersionKind", obj.GroupVersionKind(), "Name", obj.GetName(), "Fields", fields)
+		return &Conflict{Object: obj, Fields: fields}, fmt.Errorf("fields are owned by other field managers: %s", strings.Join(fields, ", "))
+	}
+
+	// the fields were set by an earlier version of the operator, so they are taken over without being reported
+	a.log.Info("Taking over fields from legacy field managers", "GroupVersionKind", obj.GroupVersionKind(), "Name", obj.GetName(), "Fields", fields)
+	return nil, a.client.Patch(ctx, obj, client.Apply, client.ForceOwnership, defaultFieldOwner)
+}
+
+// onlyLegacyFieldManagers returns true if all the field managers are legacy field managers of the operator
+func onlyLegacyFieldManagers(managers []string) bool {
+	if len(managers) == 0 {
+		return false
+	}
+	for _, m := range managers {
+		if !slices.Contains(legacyFieldManagers, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// conflictingFields returns the descriptions of the field conflicts of a server-side apply conflict error,
+// along with the field managers owning the fields
+func conflictingFields(err error) ([]string, []string) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return []string{err.Error()}, nil
+	}
+
+	var fields, managers []string
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		fields = append(fields, cause.Message)
+		if m := conflictManagerRegexp.FindStringSubmatch(cause.Message); m != nil {
+			managers = append(managers, m[1])
+		} else {
+			managers = append(managers, "")
+		}
+	}
+	if len(fields) == 0 {
+		return []string{status.Status().Message}, nil
+	}
+	return fields, managers
 }
 
 // Delete deletes the provided objects from the cluster.
@@ -105,6 +367,7 @@ func (a *Applier) splitCrdAndOthers(objs []*unstructured.Unstructured) ([]*unstr
 }
 
 func (a *Applier) createOrUpdateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	a.setLabels(obj)
 	name := obj.GetName()
 	gvk := obj.GroupVersionKind()
 
@@ -131,3 +394,19 @@ func (a *Applier) createOrUpdateObject(ctx context.Context, obj *unstructured.Un
 
 	return nil
 }
+
+// setLabels sets the labels of the applier on the object
+func (a *Applier) setLabels(obj *unstructured.Unstructured) {
+	if len(a.labels) == 0 {
+		return
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range a.labels {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+}
@@ -3,15 +3,24 @@ package kubernetes
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/yaml"
 )
 
@@ -94,6 +103,20 @@ var _ = Describe("Apply", func() {
 				Expect(actualSvc.Namespace).Should(Equal(svc.Namespace))
 				Expect(actualSvc.Spec.Type).Should(Equal(svc.Spec.Type))
 			})
+
+			It("Should set the labels of the applier on the objects", func() {
+				deploy := v1.Deployment{
+					TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-dep", Namespace: "test-ns", Labels: map[string]string{"app": "test"}},
+				}
+
+				applier = NewApplier(ctrl.Log.WithName("test"), c, WithLabels(map[string]string{"component": "test"}))
+				Expect(applier.Apply(context.TODO(), NewManifestReader(makeManifest(&deploy)))).To(Succeed())
+
+				var actual v1.Deployment
+				Expect(c.Get(context.TODO(), client.ObjectKey{Name: "test-dep", Namespace: "test-ns"}, &actual)).To(Succeed())
+				Expect(actual.Labels).To(Equal(map[string]string{"app": "test", "component": "test"}))
+			})
 		})
 		Context("Update", func() {
 			It("Should update objects correctly", func() {
@@ -118,6 +141,207 @@ var _ = Describe("Apply", func() {
 			})
 		})
 
+		Context("ContinueOnError", func() {
+			var deploy, svc client.Object
+
+			BeforeEach(func() {
+				c = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if obj.GetName() == "test-dep" {
+							return fmt.Errorf("admission webhook denied the request")
+						}
+						return cl.Create(ctx, obj, opts...)
+					},
+				}).Build()
+				deploy = &v1.Deployment{
+					TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-dep", Namespace: "test-ns"},
+				}
+				svc = &corev1.Service{
+					TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-svc", Namespace: "test-ns"},
+				}
+			})
+
+			It("Should stop at the first failure by default", func() {
+				applier = NewApplier(ctrl.Log.WithName("test"), c)
+				err := applier.Apply(context.TODO(), NewManifestReader(makeManifest(deploy, svc)))
+				Expect(err).To(MatchError(ContainSubstring("admission webhook denied the request")))
+
+				err = c.Get(context.TODO(), client.ObjectKey{Name: "test-svc", Namespace: "test-ns"}, &corev1.Service{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("Should apply all objects and aggregate the failures", func() {
+				applier = NewApplier(ctrl.Log.WithName("test"), c, WithContinueOnError())
+				err := applier.Apply(context.TODO(), NewManifestReader(makeManifest(deploy, svc)))
+
+				var applyErrs ApplyErrors
+				Expect(errors.As(err, &applyErrs)).To(BeTrue())
+				Expect(applyErrs).To(HaveLen(1))
+				Expect(applyErrs[0].Object.GetName()).To(Equal("test-dep"))
+				Expect(err).To(MatchError(ContainSubstring("failed to apply 1 objects: Deployment test-ns/test-dep:")))
+
+				Expect(c.Get(context.TODO(), client.ObjectKey{Name: "test-svc", Namespace: "test-ns"}, &corev1.Service{})).To(Succeed())
+			})
+		})
+
+		Context("CRDs", func() {
+			const crdManifest = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+spec:
+  group: example.com
+  names:
+    kind: Foo
+    plural: foos
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+---
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: test-foo
+  namespace: test-ns
+`
+			var establish bool
+
+			BeforeEach(func() {
+				establish = true
+				mapper := meta.NewDefaultRESTMapper(nil)
+				mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+				mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"}, meta.RESTScopeNamespace)
+				c = fake.NewClientBuilder().WithRESTMapper(mapper).WithInterceptorFuncs(interceptor.Funcs{
+					// establish the CRDs once they are created, as the API server would
+					Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if err := cl.Create(ctx, obj, opts...); err != nil {
+							return err
+						}
+						u, ok := obj.(*unstructured.Unstructured)
+						if !ok || u.GetKind() != "CustomResourceDefinition" || !establish {
+							return nil
+						}
+						conditions := []interface{}{map[string]interface{}{"type": "Established", "status": "True"}}
+						Expect(unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")).To(Succeed())
+						return cl.Status().Update(ctx, u)
+					},
+				}).Build()
+				applier = NewApplier(ctrl.Log.WithName("test"), c)
+				applier.crdEstablishTimeout = 100 * time.Millisecond
+			})
+
+			It("Should apply custom resources once the CRD is established", func() {
+				Expect(applier.Apply(context.TODO(), NewManifestReader([]byte(crdManifest)))).To(Succeed())
+
+				foo := &unstructured.Unstructured{}
+				foo.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"})
+				Expect(c.Get(context.TODO(), client.ObjectKey{Name: "test-foo", Namespace: "test-ns"}, foo)).To(Succeed())
+			})
+
+			It("Should fail with the name of a CRD that is not established", func() {
+				establish = false
+				err := applier.Apply(context.TODO(), NewManifestReader([]byte(crdManifest)))
+				Expect(err).To(MatchError(ContainSubstring("CRD foos.example.com was not established within 100ms")))
+
+				foo := &unstructured.Unstructured{}
+				foo.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"})
+				err = c.Get(context.TODO(), client.ObjectKey{Name: "test-foo", Namespace: "test-ns"}, foo)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		Context("ServerSideApply", func() {
+			var (
+				patches         []client.PatchOptions
+				conflicts       int
+				conflictManager string
+			)
+
+			BeforeEach(func() {
+				patches = nil
+				conflicts = 0
+				conflictManager = "kube-controller-manager"
+				// the fake client does not support server-side apply, so the apply patches are recorded instead
+				c = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						Expect(patch.Type()).To(Equal(types.ApplyPatchType))
+						options := client.PatchOptions{}
+						options.ApplyOptions(opts)
+						patches = append(patches, options)
+						if conflicts > 0 && (options.Force == nil || !*options.Force) {
+							conflicts--
+							return apierrors.NewApplyConflict([]metav1.StatusCause{{
+								Type:    metav1.CauseTypeFieldManagerConflict,
+								Message: fmt.Sprintf(`conflict with %q using apps/v1`, conflictManager),
+								Field:   ".spec.replicas",
+							}}, "Apply failed with 1 conflict")
+						}
+						return nil
+					},
+				}).Build()
+				applier = NewApplier(ctrl.Log.WithName("test"), c)
+			})
+
+			It("Should apply objects with the blueprint-operator field owner", func() {
+				deploy := v1.Deployment{
+					TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-dep", Namespace: "test-ns"},
+				}
+				objs, err := NewManifestReader(makeManifest(&deploy)).ReadManifest()
+				Expect(err).NotTo(HaveOccurred())
+
+				actual, err := applier.ServerSideApply(context.TODO(), objs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(BeEmpty())
+				Expect(patches).To(HaveLen(1))
+				Expect(patches[0].FieldManager).To(Equal("blueprint-operator"))
+				Expect(patches[0].Force).To(BeNil())
+			})
+
+			It("Should report field conflicts without forcing ownership", func() {
+				conflicts = 1
+				deploy := v1.Deployment{
+					TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-dep", Namespace: "test-ns"},
+				}
+				objs, err := NewManifestReader(makeManifest(&deploy)).ReadManifest()
+				Expect(err).NotTo(HaveOccurred())
+
+				applier = NewApplier(ctrl.Log.WithName("test"), c, WithContinueOnError())
+				actual, err := applier.ServerSideApply(context.TODO(), objs)
+				var applyErrs ApplyErrors
+				Expect(errors.As(err, &applyErrs)).To(BeTrue())
+				Expect(applyErrs).To(HaveLen(1))
+				Expect(applyErrs[0].Err.Error()).To(ContainSubstring("kube-controller-manager"))
+				Expect(actual).To(HaveLen(1))
+				Expect(actual[0].Object.GetName()).To(Equal("test-dep"))
+				Expect(actual[0].Fields).To(Equal([]string{`conflict with "kube-controller-manager" using apps/v1`}))
+				Expect(patches).To(HaveLen(1))
+				Expect(patches[0].Force).To(BeNil())
+			})
+
+			It("Should take over fields from legacy field managers of the operator", func() {
+				conflicts = 1
+				conflictManager = "manager"
+				deploy := v1.Deployment{
+					TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-dep", Namespace: "test-ns"},
+				}
+				objs, err := NewManifestReader(makeManifest(&deploy)).ReadManifest()
+				Expect(err).NotTo(HaveOccurred())
+
+				actual, err := applier.ServerSideApply(context.TODO(), objs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(BeEmpty())
+				Expect(patches).To(HaveLen(2))
+				Expect(*patches[1].Force).To(BeTrue())
+			})
+		})
+
 		Context("Delete", func() {
 			It("Should delete manifest objects correctly", func() {
 
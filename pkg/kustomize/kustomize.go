@@ -28,33 +28,43 @@ func Render(logger logr.Logger, url string, values *v1alpha1.Values) ([]byte, er
 
 	var labels []kustypes.Label
 	var resources []string
-	var images []kustypes.Image
-	var patches []kustypes.Patch
 
 	// This shall add the following label to all manifest objects
 	labels = append(labels, kustypes.Label{Pairs: map[string]string{"com.mirantis.blueprint/controlled-by": "blueprint"}, IncludeSelectors: true})
 	resources = append(resources, url)
 
-	if values != nil {
-		for _, p := range values.Patches {
-			patches = append(kus.Patches, kustypes.Patch{
-				Path:    p.Path,
-				Patch:   p.Patch,
-				Options: p.Options,
-				Target:  convertSelector(p.Target),
-			})
-		}
-
-		for _, i := range values.Images {
-			images = append(images, convertImage(i))
-		}
-	}
-
 	kus.Resources = resources
-	kus.Patches = patches
-	kus.Images = images
+	kus.Patches, kus.Images = convertValues(values)
 	kus.Labels = labels
 
+	return build(logger, fs, kus)
+}
+
+// PostRender applies the patches and images of the values to the provided manifests, as a Helm post-renderer
+// would do with the rendered templates of a chart.
+func PostRender(logger logr.Logger, manifests []byte, values *v1alpha1.Values) ([]byte, error) {
+	fs := filesys.MakeFsInMemory()
+
+	const resourcesFile = "resources.yaml"
+	if err := fs.WriteFile(resourcesFile, manifests); err != nil {
+		logger.Error(err, "error while writing file", "File", resourcesFile, "Error", err)
+		return nil, fmt.Errorf("%v", err)
+	}
+
+	kus := kustypes.Kustomization{
+		TypeMeta: kustypes.TypeMeta{
+			APIVersion: kustypes.KustomizationVersion,
+			Kind:       kustypes.KustomizationKind,
+		},
+		Resources: []string{resourcesFile},
+	}
+	kus.Patches, kus.Images = convertValues(values)
+
+	return build(logger, fs, kus)
+}
+
+// build writes the kustomization file in the file system and returns the kustomize build output
+func build(logger logr.Logger, fs filesys.FileSystem, kus kustypes.Kustomization) ([]byte, error) {
 	kd, err := yaml.Marshal(kus)
 	if err != nil {
 		return nil, fmt.Errorf("%v", err)
@@ -88,6 +98,29 @@ func Render(logger logr.Logger, url string, values *v1alpha1.Values) ([]byte, er
 
 }
 
+// convertValues converts the patches and images of the values to their kustomize equivalents
+func convertValues(values *v1alpha1.Values) ([]kustypes.Patch, []kustypes.Image) {
+	if values == nil {
+		return nil, nil
+	}
+
+	var patches []kustypes.Patch
+	for _, p := range values.Patches {
+		patches = append(patches, kustypes.Patch{
+			Path:    p.Path,
+			Patch:   p.Patch,
+			Options: p.Options,
+			Target:  convertSelector(p.Target),
+		})
+	}
+
+	var images []kustypes.Image
+	for _, i := range values.Images {
+		images = append(images, convertImage(i))
+	}
+	return patches, images
+}
+
 func convertSelector(target *v1alpha1.Selector) *kustypes.Selector {
 	if target == nil {
 		return nil
@@ -3,15 +3,21 @@ package webhook
 import (
 	"context"
 	"fmt"
-	"slices"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/controllers/helm"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/dag"
 )
 
 const (
@@ -26,7 +32,7 @@ func SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&v1alpha1.Blueprint{}).
 		WithDefaulter(&blueprintDefaulter{}).
-		WithValidator(&blueprintValidator{}).
+		WithValidator(&blueprintValidator{reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
@@ -41,13 +47,20 @@ func (r *blueprintDefaulter) Default(ctx context.Context, obj runtime.Object) er
 		return fmt.Errorf("obj %v is not a blueprint kind", obj.GetObjectKind())
 	}
 	blueprintlog.Info("default", "name", blueprint.Name)
+	if blueprint.Spec.DeletionPolicy == "" {
+		blueprint.Spec.DeletionPolicy = v1alpha1.DeletionPolicyDelete
+	}
 	return nil
 }
 
 // change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 //+kubebuilder:webhook:path=/validate-blueprint-mirantis-com-v1alpha1-blueprint,mutating=false,failurePolicy=fail,sideEffects=None,groups=blueprint.mirantis.com,resources=blueprints,verbs=create;update,versions=v1alpha1,name=vblueprint.kb.io,admissionReviewVersions=v1
 
-type blueprintValidator struct{}
+type blueprintValidator struct {
+	// reader is used to check that the objects referenced by the blueprint exist
+	// It reads from the API server directly so that Secrets are not cached by the operator.
+	reader client.Reader
+}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *blueprintValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -56,7 +69,7 @@ func (r *blueprintValidator) ValidateCreate(ctx context.Context, obj runtime.Obj
 		return nil, fmt.Errorf("obj %v is not a blueprint kind", obj.GetObjectKind())
 	}
 	blueprintlog.Info("validate create", "name", blueprint.Name)
-	return validate(blueprint.Spec)
+	return r.validate(ctx, blueprint)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -66,7 +79,12 @@ func (r *blueprintValidator) ValidateUpdate(ctx context.Context, oldObj, newObj
 		return nil, fmt.Errorf("obj %v is not a blueprint kind", newObj.GetObjectKind())
 	}
 	blueprintlog.Info("validate update", "name", blueprint.Name)
-	return validate(blueprint.Spec)
+	if !blueprint.DeletionTimestamp.IsZero() {
+		// the blueprint is being torn down, e.g. its finalizer is being removed, which must not be blocked
+		// by objects it references having been deleted in the meantime
+		return nil, nil
+	}
+	return r.validate(ctx, blueprint)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -80,16 +98,28 @@ func (r *blueprintValidator) ValidateDelete(ctx context.Context, obj runtime.Obj
 	return nil, nil
 }
 
+// validate validates the blueprint spec, checks that the Secrets it references exist and that its addons are not
+// declared by another blueprint
+func (r *blueprintValidator) validate(ctx context.Context, blueprint *v1alpha1.Blueprint) (admission.Warnings, error) {
+	warnings, err := validate(blueprint.Spec)
+	if err != nil {
+		return warnings, err
+	}
+
+	if err = validateSecretRefs(ctx, r.reader, blueprint.Spec.Components.Addons); err != nil {
+		return warnings, err
+	}
+	if err = validateAddonNames(ctx, r.reader, blueprint); err != nil {
+		return warnings, err
+	}
+	return warnings, nil
+}
+
 func validate(spec v1alpha1.BlueprintSpec) (admission.Warnings, error) {
 	if len(spec.Components.Addons) == 0 {
 		return nil, nil
 	}
 
-	var addonNames []string
-	for _, a := range spec.Components.Addons {
-		addonNames = append(addonNames, a.Name)
-	}
-
 	for _, val := range spec.Components.Addons {
 		if strings.EqualFold(kindChart, val.Kind) {
 			if val.Manifest != nil {
@@ -100,11 +130,26 @@ func validate(spec v1alpha1.BlueprintSpec) (admission.Warnings, error) {
 				blueprintlog.Info("received empty chart object.", "Kind", kindChart)
 				return nil, fmt.Errorf("chart object can't be empty for addon kind %s", kindChart)
 			}
-			if len(val.Chart.DependsOn) > 0 {
-				for _, dep := range val.Chart.DependsOn {
-					if !slices.Contains(addonNames, dep) {
-						return nil, fmt.Errorf("addon %s depends on %s which is not present in the list of addons", val.Name, dep)
-					}
+			for path := range val.Chart.Set {
+				if err := helm.ValidateSetPath(path); err != nil {
+					return nil, fmt.Errorf("addon %s has invalid set path %q: %w", val.Name, path, err)
+				}
+			}
+			if err := helm.ValidateHelmOptions(val.Chart.HelmOptions); err != nil {
+				return nil, fmt.Errorf("addon %s has invalid helm options: %w", val.Name, err)
+			}
+			if err := helm.ValidateTimeout(val.Chart); err != nil {
+				return nil, fmt.Errorf("addon %s has invalid timeout or failure policy: %w", val.Name, err)
+			}
+			if err := helm.ValidatePostRenderer(val.Chart.Kustomize); err != nil {
+				return nil, fmt.Errorf("addon %s has invalid kustomize values: %w", val.Name, err)
+			}
+			for _, ref := range val.Chart.ValuesFrom {
+				if ref.TargetPath == "" {
+					continue
+				}
+				if err := helm.ValidateSetPath(ref.TargetPath); err != nil {
+					return nil, fmt.Errorf("addon %s has invalid target path %q for %s %s: %w", val.Name, ref.TargetPath, ref.Kind, ref.Name, err)
 				}
 			}
 		}
@@ -121,5 +166,97 @@ func validate(spec v1alpha1.BlueprintSpec) (admission.Warnings, error) {
 		}
 	}
 
+	if err := validateDependencies(spec.Components.Addons); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
+
+// validateDependencies checks that addons only depend on enabled addons of the blueprint and that the
+// dependencies don't form a cycle
+func validateDependencies(addons []v1alpha1.AddonSpec) error {
+	enabled := map[string]bool{}
+	for _, a := range addons {
+		enabled[a.Name] = a.Enabled
+	}
+
+	graph := dag.New()
+	for _, a := range addons {
+		graph.AddNode(a.Name)
+		for _, dep := range a.AllDependencies() {
+			isEnabled, ok := enabled[dep]
+			if !ok {
+				return fmt.Errorf("addon %s depends on %s which is not present in the list of addons", a.Name, dep)
+			}
+			if a.Enabled && !isEnabled {
+				return fmt.Errorf("addon %s depends on %s which is not enabled", a.Name, dep)
+			}
+			graph.AddNode(a.Name, dep)
+		}
+	}
+
+	if _, err := graph.TopologicalSort(); err != nil {
+		return fmt.Errorf("invalid addon dependencies: %w", err)
+	}
+	return nil
+}
+
+// validateSecretRefs checks that the Secrets referenced by the chart addons exist in the blueprint-system namespace
+func validateSecretRefs(ctx context.Context, reader client.Reader, addons []v1alpha1.AddonSpec) error {
+	for _, a := range addons {
+		if a.Chart == nil {
+			continue
+		}
+
+		for _, ref := range []*v1alpha1.SecretReference{a.Chart.SecretRef, a.Chart.CertSecretRef} {
+			if ref == nil {
+				continue
+			}
+
+			key := types.NamespacedName{Namespace: consts.NamespaceBlueprintSystem, Name: ref.Name}
+			if err := reader.Get(ctx, key, &corev1.Secret{}); err != nil {
+				if apierrors.IsNotFound(err) {
+					return fmt.Errorf("addon %s references secret %s which does not exist in namespace %s", a.Name, ref.Name, consts.NamespaceBlueprintSystem)
+				}
+				return fmt.Errorf("failed to get secret %s referenced by addon %s: %w", ref.Name, a.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAddonNames checks that the addons of the blueprint are not declared by another blueprint
+// Addons are all created in the blueprint-system namespace, so two blueprints can't declare addons with the same
+// name. Blueprints being deleted are ignored: the operator only takes over their addons once they have been
+// uninstalled or orphaned.
+func validateAddonNames(ctx context.Context, reader client.Reader, blueprint *v1alpha1.Blueprint) error {
+	if len(blueprint.Spec.Components.Addons) == 0 {
+		return nil
+	}
+
+	blueprints := &v1alpha1.BlueprintList{}
+	if err := reader.List(ctx, blueprints); err != nil {
+		return fmt.Errorf("failed to list blueprints: %w", err)
+	}
+
+	names := map[string]bool{}
+	for _, a := range blueprint.Spec.Components.Addons {
+		names[a.Name] = true
+	}
+
+	for _, other := range blueprints.Items {
+		if other.Name == blueprint.Name && other.Namespace == blueprint.Namespace {
+			continue
+		}
+		if !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, a := range other.Spec.Components.Addons {
+			if names[a.Name] {
+				return fmt.Errorf("addon %s is already declared by blueprint %s/%s", a.Name, other.Namespace, other.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/mirantiscontainers/blueprint-operator/api/v1alpha1"
+	"github.com/mirantiscontainers/blueprint-operator/pkg/consts"
+)
+
+func TestValidateChartSet(t *testing.T) {
+	tests := []struct {
+		name        string
+		set         map[string]intstr.IntOrString
+		expectError bool
+	}{
+		{
+			name: "valid set paths",
+			set: map[string]intstr.IntOrString{
+				"replicas":                       intstr.FromInt32(2),
+				"servers[0].port":                intstr.FromInt32(80),
+				`podAnnotations.example\.com/id`: intstr.FromString("x"),
+			},
+		},
+		{
+			name:        "empty key",
+			set:         map[string]intstr.IntOrString{"image..tag": intstr.FromString("1.0")},
+			expectError: true,
+		},
+		{
+			name:        "invalid list index",
+			set:         map[string]intstr.IntOrString{"servers[a].port": intstr.FromInt32(80)},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := v1alpha1.BlueprintSpec{
+				Components: v1alpha1.Component{
+					Addons: []v1alpha1.AddonSpec{
+						{
+							Name: "test",
+							Kind: kindChart,
+							Chart: &v1alpha1.ChartInfo{
+								Name:    "test",
+								Repo:    "https://charts.example.com",
+								Version: "1.0.0",
+								Set:     tc.set,
+							},
+						},
+					},
+				},
+			}
+
+			_, err := validate(spec)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	manifest := func(name string, enabled bool, dependsOn ...string) v1alpha1.AddonSpec {
+		return v1alpha1.AddonSpec{
+			Name:      name,
+			Kind:      kindManifest,
+			Enabled:   enabled,
+			Manifest:  &v1alpha1.ManifestInfo{URL: "https://example.com/" + name + ".yaml"},
+			DependsOn: dependsOn,
+		}
+	}
+	chart := func(name string, dependsOn ...string) v1alpha1.AddonSpec {
+		return v1alpha1.AddonSpec{
+			Name:    name,
+			Kind:    kindChart,
+			Enabled: true,
+			Chart: &v1alpha1.ChartInfo{
+				Name:      name,
+				Repo:      "https://charts.example.com",
+				Version:   "1.0.0",
+				DependsOn: dependsOn,
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		addons        []v1alpha1.AddonSpec
+		expectedError string
+	}{
+		{
+			name:   "chart depends on manifest",
+			addons: []v1alpha1.AddonSpec{manifest("cni", true), chart("app", "cni")},
+		},
+		{
+			name:   "manifest depends on chart",
+			addons: []v1alpha1.AddonSpec{chart("operator"), manifest("crs", true, "operator")},
+		},
+		{
+			name:          "missing dependency",
+			addons:        []v1alpha1.AddonSpec{manifest("crs", true, "operator")},
+			expectedError: "not present in the list of addons",
+		},
+		{
+			name:          "disabled dependency",
+			addons:        []v1alpha1.AddonSpec{manifest("cni", false), manifest("csi", true, "cni")},
+			expectedError: "not enabled",
+		},
+		{
+			name:          "self dependency",
+			addons:        []v1alpha1.AddonSpec{manifest("cni", true, "cni")},
+			expectedError: "cycle detected: cni -> cni",
+		},
+		{
+			name:          "cycle across kinds",
+			addons:        []v1alpha1.AddonSpec{manifest("a", true, "b"), chart("b", "c"), manifest("c", true, "a")},
+			expectedError: "cycle detected: a -> b -> c -> a",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validate(v1alpha1.BlueprintSpec{Components: v1alpha1.Component{Addons: tc.addons}})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateSecretRefs(t *testing.T) {
+	reader := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor-auth", Namespace: consts.NamespaceBlueprintSystem},
+	}).Build()
+
+	chart := func(secretRef, certSecretRef *v1alpha1.SecretReference) v1alpha1.AddonSpec {
+		return v1alpha1.AddonSpec{
+			Name: "app",
+			Kind: kindChart,
+			Chart: &v1alpha1.ChartInfo{
+				Name:          "app",
+				Repo:          "https://harbor.example.com/chartrepo/library",
+				Version:       "1.0.0",
+				SecretRef:     secretRef,
+				CertSecretRef: certSecretRef,
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		addon         v1alpha1.AddonSpec
+		expectedError string
+	}{
+		{
+			name:  "public repository",
+			addon: chart(nil, nil),
+		},
+		{
+			name:  "existing secret",
+			addon: chart(&v1alpha1.SecretReference{Name: "harbor-auth"}, nil),
+		},
+		{
+			name:          "missing secret",
+			addon:         chart(&v1alpha1.SecretReference{Name: "missing"}, nil),
+			expectedError: "addon app references secret missing which does not exist",
+		},
+		{
+			name:          "missing CA secret",
+			addon:         chart(&v1alpha1.SecretReference{Name: "harbor-auth"}, &v1alpha1.SecretReference{Name: "harbor-ca"}),
+			expectedError: "addon app references secret harbor-ca which does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSecretRefs(context.TODO(), reader, []v1alpha1.AddonSpec{tc.addon})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateAddonNames(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	blueprint := func(namespace, name string, addons ...string) *v1alpha1.Blueprint {
+		b := &v1alpha1.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		for _, a := range addons {
+			b.Spec.Components.Addons = append(b.Spec.Components.Addons, v1alpha1.AddonSpec{Name: a, Kind: kindManifest})
+		}
+		return b
+	}
+	deleting := blueprint("team-c", "legacy", "ingress")
+	deleting.Finalizers = []string{"blueprint.mirantis.com/blueprint-finalizer"}
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		blueprint("team-a", "platform", "cni", "csi"),
+		blueprint("team-b", "apps", "wordpress"),
+		deleting,
+	).Build()
+
+	tests := []struct {
+		name          string
+		blueprint     *v1alpha1.Blueprint
+		expectedError string
+	}{
+		{
+			name:      "update of an existing blueprint",
+			blueprint: blueprint("team-a", "platform", "cni", "csi", "metrics"),
+		},
+		{
+			name:      "distinct addons",
+			blueprint: blueprint("team-b", "other", "redis"),
+		},
+		{
+			name:          "addon declared by another blueprint",
+			blueprint:     blueprint("team-b", "apps", "wordpress", "cni"),
+			expectedError: "addon cni is already declared by blueprint team-a/platform",
+		},
+		{
+			name:          "blueprint with the same name in another namespace",
+			blueprint:     blueprint("team-b", "platform", "csi"),
+			expectedError: "addon csi is already declared by blueprint team-a/platform",
+		},
+		{
+			name:      "addon of a blueprint being deleted",
+			blueprint: blueprint("team-b", "apps", "wordpress", "ingress"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAddonNames(context.TODO(), reader, tc.blueprint)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}